@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// runCheckAPI compares the public API of original files with their converted counterparts
+func runCheckAPI(args []string) int {
+	flags := flag.NewFlagSet("check-api", flag.ExitOnError)
+	original := flags.String("original", "", "Original source file or directory (required)")
+	converted := flags.String("converted", "", "Converted file or output directory (required)")
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	reportPath := flags.String("report", "", "JSON file to write the API comparison to")
+	flags.Parse(args)
+
+	if *original == "" || *converted == "" {
+		fmt.Println("Error: original and converted flags are required")
+		flags.Usage()
+		return 1
+	}
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+
+	results, err := converter.CheckAPIParity(*original, *converted)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	failed := 0
+	for _, r := range results {
+		status := "OK  "
+		if !r.OK() {
+			status = "DIFF"
+			failed++
+		}
+		fmt.Printf("%s %s -> %s: %s\n", status, r.Source, r.Output, r.Summary())
+		for _, line := range r.Details() {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Printf("Checked: %d files, %d with API differences\n", len(results), failed)
+
+	if *reportPath != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Printf("Error encoding report: %v\n", err)
+			return 1
+		}
+		if err := os.WriteFile(*reportPath, append(data, '\n'), 0644); err != nil {
+			fmt.Printf("Error writing report: %v\n", err)
+			return 1
+		}
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// runCompare converts the same input with several configurations into side-by-side trees and compares them
+func runCompare(args []string) int {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	var inputs, providerNames inputList
+	flags.Var(&inputs, "input", "Input directory, file, comma-separated list, glob or @filelist; may be repeated (required)")
+	outputDir := flags.String("output", "", "Directory for the output trees, one subdirectory per configuration (required)")
+	targetLang := flags.String("lang", "", "Target language, optionally with a version, platform and modifiers (required)")
+	sourceLang := flags.String("from", "", "Source language of every text file, overriding detection; binary files are copied (detected if omitted)")
+	layoutName := flags.String("layout", "mirror", "Output layout: mirror, idiomatic or flat")
+	flags.Var(&providerNames, "provider", "Provider to compare, one configuration each; comma-separated or repeated")
+	configPath := flags.String("config", "", "JSON file with configurations, providers and model prices, as for eval")
+	fake := flags.String("fake", "", "Register a provider named fake that answers from a JSON fixture file")
+	replay := flags.String("replay", "", "Register a provider named replay that answers from a recorded JSONL transcript")
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	egress := addEgressFlags(flags)
+	reportName := flags.String("report", "compare-report.json", "JSON comparison report, relative to the output directory; empty to disable")
+	flags.Parse(args)
+
+	if len(inputs) == 0 || *outputDir == "" || *targetLang == "" {
+		fmt.Println("Error: input, output, and lang flags are required")
+		flags.Usage()
+		return 1
+	}
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if _, err := converter.ResolveLanguage(converter.ParseTargetSpec(*targetLang).Name); err != nil {
+		fmt.Printf("Error: -lang: %v\n", err)
+		return 1
+	}
+	if *sourceLang != "" {
+		lang, err := converter.ResolveLanguage(*sourceLang)
+		if err != nil {
+			fmt.Printf("Error: -from: %v\n", err)
+			return 1
+		}
+		*sourceLang = lang.Name
+	}
+
+	layout, err := converter.ParseLayout(*layoutName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	inputPaths, err := expandInputs(inputs, os.Stdin)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	if err := registerOfflineProviders(*fake, *replay); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	// The policy is loaded first, since configurations may name the providers it declares
+	policy, err := egress.loadPolicy()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	comparer := converter.NewComparer(inputPaths, *outputDir, *targetLang)
+	comparer.SetSourceLanguage(*sourceLang)
+	comparer.SetLayout(layout)
+	comparer.SetEgressPolicy(policy)
+	comparer.SetBlockOnSecrets(*egress.blockOnSecrets)
+
+	var configs []converter.EvalConfig
+	if *configPath != "" {
+		loaded, prices, err := converter.LoadEvalConfigs(*configPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		configs = loaded
+		comparer.SetPrices(prices)
+	}
+	for _, name := range providerNames {
+		configs = append(configs, converter.EvalConfig{Name: name, Provider: name})
+	}
+	comparer.SetConfigs(configs)
+
+	report, err := comparer.Compare()
+	if err != nil {
+		fmt.Printf("Error during comparison: %v\n", err)
+		return 1
+	}
+
+	fmt.Println()
+	if err := report.WriteTable(os.Stdout); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	if *reportName != "" {
+		reportPath := *reportName
+		if !filepath.IsAbs(reportPath) {
+			reportPath = filepath.Join(*outputDir, reportPath)
+		}
+		if err := converter.WriteCompareReport(report, reportPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Comparison written to %s\n", reportPath)
+	}
+	return 0
+}
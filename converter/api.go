@@ -0,0 +1,592 @@
+package converter
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Symbol is an element of a file's public API
+type Symbol struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Parent string `json:"parent,omitempty"`
+}
+
+func (s Symbol) String() string {
+	if s.Parent != "" {
+		return s.Kind + " " + s.Parent + "." + s.Name
+	}
+	return s.Kind + " " + s.Name
+}
+
+// APIRename pairs a source symbol with the differently named output symbol that appears to replace it
+type APIRename struct {
+	From Symbol `json:"from"`
+	To   Symbol `json:"to"`
+}
+
+// APIParity compares the public API of a source file with its converted output.
+// Symbols whose names differ only by naming convention, such as GetUserByID and
+// get_user_by_id, count as matched.
+type APIParity struct {
+	Source  string      `json:"source,omitempty"`
+	Output  string      `json:"output,omitempty"`
+	Matched int         `json:"matched"`
+	Missing []Symbol    `json:"missing,omitempty"`
+	Renamed []APIRename `json:"renamed,omitempty"`
+	Extra   []Symbol    `json:"extra,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// OK reports whether every source symbol has a counterpart with a matching name
+func (p APIParity) OK() bool {
+	return p.Error == "" && len(p.Missing) == 0 && len(p.Renamed) == 0
+}
+
+// Summary counts the matches and differences, e.g. "4 matched, 2 missing, 1 renamed, 0 extra"
+func (p APIParity) Summary() string {
+	if p.Error != "" {
+		return p.Error
+	}
+	return fmt.Sprintf("%d matched, %d missing, %d renamed, %d extra", p.Matched, len(p.Missing), len(p.Renamed), len(p.Extra))
+}
+
+// Details lists every difference on its own line, e.g. "missing function DeleteUser"
+func (p APIParity) Details() []string {
+	var lines []string
+	for _, s := range p.Missing {
+		lines = append(lines, "missing "+s.String())
+	}
+	for _, r := range p.Renamed {
+		lines = append(lines, "renamed "+r.From.String()+" -> "+r.To.Name)
+	}
+	for _, s := range p.Extra {
+		lines = append(lines, "extra   "+s.String())
+	}
+	return lines
+}
+
+// SetCheckAPI enables comparing the public API of every converted file with its source
+func (c *Converter) SetCheckAPI(enabled bool) {
+	c.checkAPI = enabled
+}
+
+// symbolPattern extracts symbols of one kind. Patterns capture the name in a
+// group called "name" and may capture modifiers in a group called "mods", which
+// mark the symbol private when they contain one of the language's private modifiers.
+type symbolPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// symbolParser extracts the public API of one language; private matches the
+// modifiers that keep a symbol out of it, such as private in Kotlin or static in C
+type symbolParser struct {
+	patterns []symbolPattern
+	private  *regexp.Regexp
+}
+
+var (
+	jsSymbolPatterns = []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^\s*export\s+(?:default\s+)?(?:async\s+)?function\*?\s+(?P<name>\w+)`)},
+		{"type", regexp.MustCompile(`(?m)^\s*export\s+(?:default\s+)?(?:abstract\s+)?class\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*export\s+(?:const|let|var)\s+(?P<name>\w+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|\w+\s*=>)`)},
+		{"variable", regexp.MustCompile(`(?m)^\s*export\s+(?:const|let|var)\s+(?P<name>\w+)`)},
+		{"variable", regexp.MustCompile(`(?m)^\s*(?:module\.)?exports\.(?P<name>\w+)\s*=`)},
+	}
+	cSymbolPatterns = []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^(?P<mods>[\w:<>*&, ]+?)[\s*&]+(?P<name>[A-Za-z_][\w:~]*)\s*\([^;{}]*?\)\s*(?:const\s*)?(?:noexcept\s*)?\{`)},
+		{"type", regexp.MustCompile(`(?m)^\s*(?:typedef\s+)?(?:struct|class|enum(?:\s+class)?|union)\s+(?P<name>\w+)\s*[{:]`)},
+		{"type", regexp.MustCompile(`(?m)^typedef\s+[^;]*?\b(?P<name>\w+)\s*;`)},
+		{"constant", regexp.MustCompile(`(?m)^#define\s+(?P<name>[A-Z][A-Z0-9_]*)\b`)},
+	}
+	// cPrivateModifiers mark functions with internal linkage
+	cPrivateModifiers = regexp.MustCompile(`\bstatic\b`)
+)
+
+// symbolParsers hold the lightweight API parsers for languages without a dedicated one, keyed by language name
+var symbolParsers = map[string]symbolParser{
+	"JavaScript": {patterns: jsSymbolPatterns},
+	"TypeScript": {patterns: append([]symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*export\s+(?:declare\s+)?(?:interface|type|enum|const\s+enum)\s+(?P<name>\w+)`)},
+	}, jsSymbolPatterns...)},
+	"Java": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*public\s+(?:(?:static|final|abstract|sealed|non-sealed)\s+)*(?:class|interface|enum|record|@interface)\s+(?P<name>\w+)`)},
+		{"constant", regexp.MustCompile(`(?m)^\s*public\s+static\s+final\s+[\w<>\[\], ?.]+\s+(?P<name>\w+)\s*=`)},
+		{"method", regexp.MustCompile(`(?m)^\s*public\s+(?:(?:static|final|abstract|synchronized|default|native)\s+)*(?:<[^>]+>\s+)?[\w<>\[\], ?.]+\s+(?P<name>\w+)\s*\(`)},
+	}},
+	"C#": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*public\s+(?:(?:static|sealed|abstract|partial|readonly)\s+)*(?:class|interface|enum|struct|record)\s+(?P<name>\w+)`)},
+		{"constant", regexp.MustCompile(`(?m)^\s*public\s+(?:const|static\s+readonly)\s+[\w<>\[\], ?.]+\s+(?P<name>\w+)\s*=`)},
+		{"method", regexp.MustCompile(`(?m)^\s*public\s+(?:(?:static|virtual|override|abstract|async|sealed|new|extern)\s+)*[\w<>\[\], ?.]+\s+(?P<name>\w+)\s*(?:<[^>]+>)?\s*\(`)},
+		{"variable", regexp.MustCompile(`(?m)^\s*public\s+(?:(?:static|virtual|override|abstract|required)\s+)*[\w<>\[\], ?.]+\s+(?P<name>\w+)\s*\{\s*get`)},
+	}},
+	"Kotlin": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:\w+\s+)*)(?:class|interface|object)\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:\w+\s+)*)fun\s+(?:<[^>]+>\s*)?(?:[\w.]+\.)?(?P<name>\w+)\s*\(`)},
+		{"constant", regexp.MustCompile(`(?m)^(?P<mods>(?:\w+\s+)*)val\s+(?P<name>[A-Z][A-Z0-9_]*)\b`)},
+	}, private: regexp.MustCompile(`\b(private|protected|internal)\b`)},
+	"Scala": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:\w+\s+)*)(?:class|trait|object|enum)\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:\w+\s+)*)def\s+(?P<name>\w+)`)},
+	}, private: regexp.MustCompile(`\b(private|protected)\b`)},
+	"Swift": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:\w+\s+)*)(?:class|struct|enum|protocol|actor)\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:@\w+\s+)*(?:\w+\s+)*)func\s+(?P<name>\w+)`)},
+		{"constant", regexp.MustCompile(`(?m)^(?P<mods>(?:\w+\s+)*)let\s+(?P<name>\w+)\s*[:=]`)},
+	}, private: regexp.MustCompile(`\b(private|fileprivate|internal)\b`)},
+	"Rust": {patterns: []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^\s*pub\s+(?:(?:async|const|unsafe|extern\s+"\w+")\s+)*fn\s+(?P<name>\w+)`)},
+		{"type", regexp.MustCompile(`(?m)^\s*pub\s+(?:struct|enum|trait|type|union)\s+(?P<name>\w+)`)},
+		{"constant", regexp.MustCompile(`(?m)^\s*pub\s+(?:const|static)\s+(?:mut\s+)?(?P<name>\w+)`)},
+	}},
+	"Ruby": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?:class|module)\s+(?P<name>[A-Z]\w*)`)},
+		{"method", regexp.MustCompile(`(?m)^\s*def\s+(?:self\.)?(?P<name>\w+[?!=]?)`)},
+		{"constant", regexp.MustCompile(`(?m)^\s*(?P<name>[A-Z][A-Z0-9_]*)\s*=`)},
+	}},
+	"PHP": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?:(?:abstract|final|readonly)\s+)*(?:class|interface|trait|enum)\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:(?:public|private|protected|static|abstract|final)\s+)*)function\s+(?P<name>\w+)`)},
+		{"constant", regexp.MustCompile(`(?m)^\s*(?P<mods>(?:(?:public|private|protected|final)\s+)*)const\s+(?P<name>\w+)`)},
+	}, private: regexp.MustCompile(`\b(private|protected)\b`)},
+	"C":   {patterns: cSymbolPatterns, private: cPrivateModifiers},
+	"C++": {patterns: cSymbolPatterns, private: cPrivateModifiers},
+	"Objective-C": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^@(?:interface|protocol)\s+(?P<name>\w+)`)},
+		{"method", regexp.MustCompile(`(?m)^[-+]\s*\([^)]*\)\s*(?P<name>\w+)`)},
+	}},
+	"Elixir": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*defmodule\s+(?P<name>[\w.]+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*def(?:macro)?\s+(?P<name>\w+[?!]?)`)},
+	}},
+	"Dart": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*(?:abstract\s+)?(?:class|mixin|enum|extension)\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*(?:static\s+)?(?:[\w<>?,\[\]]+\s+)?(?P<name>[A-Za-z_]\w*)\s*\([^)]*\)\s*(?:async\s*)?(?:\{|=>)`)},
+	}},
+	"Lua": {patterns: []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^\s*(?P<mods>local\s+)?function\s+(?:\w+[.:])?(?P<name>\w+)\s*\(`)},
+		{"function", regexp.MustCompile(`(?m)^\s*\w+\.(?P<name>\w+)\s*=\s*function\b`)},
+	}, private: regexp.MustCompile(`\blocal\b`)},
+	"Haskell": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^(?:data|newtype|type|class)\s+(?P<name>[A-Z]\w*)`)},
+		{"function", regexp.MustCompile(`(?m)^(?P<name>[a-z_]\w*'?)\s*::`)},
+	}},
+	"Perl": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*package\s+(?P<name>[\w:]+)\s*;`)},
+		{"function", regexp.MustCompile(`(?m)^\s*sub\s+(?P<name>\w+)`)},
+	}},
+	"R": {patterns: []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^(?P<name>[\w.]+)\s*(?:<-|=)\s*function\b`)},
+	}},
+	"Shell": {patterns: []symbolPattern{
+		{"function", regexp.MustCompile(`(?m)^\s*(?:function\s+)?(?P<name>[\w:-]+)\s*\(\s*\)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*function\s+(?P<name>[\w:-]+)\s*\{`)},
+	}},
+	"PowerShell": {patterns: []symbolPattern{
+		{"type", regexp.MustCompile(`(?m)^\s*class\s+(?P<name>\w+)`)},
+		{"function", regexp.MustCompile(`(?m)^\s*function\s+(?P<name>[\w-]+)`)},
+	}},
+}
+
+// nonSymbols are keywords that the lightweight parsers can mistake for function names
+var nonSymbols = toSet("if", "for", "while", "switch", "catch", "return", "sizeof", "main")
+
+// extractSymbols returns the public API of a source file in the given language
+func extractSymbols(lang, content string) ([]Symbol, error) {
+	switch lang {
+	case "Go":
+		return goSymbols(content)
+	case "Python":
+		return pythonSymbols(content), nil
+	}
+
+	parser, ok := symbolParsers[lang]
+	if !ok {
+		return nil, fmt.Errorf("no API parser for %s", lang)
+	}
+
+	var symbols []Symbol
+	seen := map[string]bool{}
+	for _, p := range parser.patterns {
+		nameIdx, modsIdx := p.re.SubexpIndex("name"), p.re.SubexpIndex("mods")
+		for _, m := range p.re.FindAllStringSubmatch(content, -1) {
+			name := m[nameIdx]
+			if modsIdx >= 0 && parser.private != nil && parser.private.MatchString(m[modsIdx]) {
+				continue
+			}
+			kind := p.kind
+			// C++ member function definitions look like User::save
+			if i := strings.LastIndex(name, "::"); i >= 0 {
+				name, kind = name[i+2:], "method"
+			}
+			if nonSymbols[name] || strings.HasPrefix(name, "_") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			symbols = append(symbols, Symbol{Name: name, Kind: kind})
+		}
+	}
+
+	if lang == "JavaScript" || lang == "TypeScript" {
+		symbols = append(symbols, jsExportLists(content, seen)...)
+		// A script without exports exposes its top-level declarations as globals
+		if len(symbols) == 0 {
+			symbols = jsGlobals(content)
+		}
+	}
+	return symbols, nil
+}
+
+// jsGlobalPatterns match declarations at the top level of a script
+var jsGlobalPatterns = []symbolPattern{
+	{"function", regexp.MustCompile(`(?m)^(?:async\s+)?function\*?\s+(?P<name>\w+)`)},
+	{"type", regexp.MustCompile(`(?m)^class\s+(?P<name>\w+)`)},
+	{"constant", regexp.MustCompile(`(?m)^const\s+(?P<name>[A-Z][A-Z0-9_]*)\s*=`)},
+}
+
+// jsGlobals returns the top-level functions, classes and constants of a script
+func jsGlobals(content string) []Symbol {
+	var symbols []Symbol
+	for _, p := range jsGlobalPatterns {
+		for _, m := range p.re.FindAllStringSubmatch(content, -1) {
+			symbols = append(symbols, Symbol{Name: m[p.re.SubexpIndex("name")], Kind: p.kind})
+		}
+	}
+	return symbols
+}
+
+// jsExportListPatterns match "export { a, b as c }" and "module.exports = { a, b }"
+var jsExportListPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^\s*export\s*\{([^}]*)\}`),
+	regexp.MustCompile(`module\.exports\s*=\s*\{([^}]*)\}`),
+}
+
+// jsExportLists returns the names listed in export and module.exports object literals
+func jsExportLists(content string, seen map[string]bool) []Symbol {
+	var symbols []Symbol
+	for _, re := range jsExportListPatterns {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			for _, item := range strings.Split(m[1], ",") {
+				fields := strings.Fields(strings.NewReplacer(":", " ", "(", " ").Replace(item))
+				if len(fields) == 0 {
+					continue
+				}
+				name := fields[0]
+				if len(fields) >= 3 && fields[1] == "as" {
+					name = fields[2]
+				}
+				if !seen[name] {
+					seen[name] = true
+					symbols = append(symbols, Symbol{Name: name, Kind: "variable"})
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// goSymbols extracts exported declarations with go/ast
+func goSymbols(content string) ([]Symbol, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go source: %w", err)
+	}
+
+	var symbols []Symbol
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				symbols = append(symbols, Symbol{Name: d.Name.Name, Kind: "function"})
+				continue
+			}
+			receiver := receiverName(d.Recv.List[0].Type)
+			if ast.IsExported(receiver) {
+				symbols = append(symbols, Symbol{Name: d.Name.Name, Kind: "method", Parent: receiver})
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						symbols = append(symbols, Symbol{Name: s.Name.Name, Kind: "type"})
+					}
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == token.CONST {
+						kind = "constant"
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							symbols = append(symbols, Symbol{Name: name.Name, Kind: kind})
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// receiverName returns the type name of a method receiver such as *User or List[T]
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+var (
+	pythonDef      = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+(\w+)`)
+	pythonClass    = regexp.MustCompile(`^(\s*)class\s+(\w+)`)
+	pythonConstant = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)\s*(?::[^=]+)?=`)
+)
+
+// pythonSymbols extracts public module-level functions, classes, constants and the
+// methods defined directly in public classes, using indentation to find class bodies
+func pythonSymbols(content string) []Symbol {
+	var symbols []Symbol
+	class, classIndent, methodIndent := "", -1, -1
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if class != "" && indent <= classIndent {
+			class, classIndent, methodIndent = "", -1, -1
+		}
+
+		if m := pythonClass.FindStringSubmatch(line); m != nil && indent == 0 {
+			if !strings.HasPrefix(m[2], "_") {
+				symbols = append(symbols, Symbol{Name: m[2], Kind: "type"})
+				class, classIndent = m[2], 0
+			}
+			continue
+		}
+		if m := pythonDef.FindStringSubmatch(line); m != nil {
+			name := m[2]
+			switch {
+			case indent == 0:
+				if !strings.HasPrefix(name, "_") {
+					symbols = append(symbols, Symbol{Name: name, Kind: "function"})
+				}
+			case class != "":
+				if methodIndent < 0 {
+					methodIndent = indent
+				}
+				if indent == methodIndent && !strings.HasPrefix(name, "_") {
+					symbols = append(symbols, Symbol{Name: name, Kind: "method", Parent: class})
+				}
+			}
+			continue
+		}
+		if m := pythonConstant.FindStringSubmatch(line); m != nil && indent == 0 {
+			symbols = append(symbols, Symbol{Name: m[1], Kind: "constant"})
+		}
+	}
+	return symbols
+}
+
+// apiKey normalises a name across naming conventions, so GetUserByID, getUserById
+// and get_user_by_id share a key
+func apiKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// kindGroup treats functions and methods, and constants and variables, as interchangeable across languages
+func kindGroup(kind string) string {
+	switch kind {
+	case "function", "method":
+		return "callable"
+	case "constant", "variable":
+		return "value"
+	}
+	return kind
+}
+
+// compareAPI matches source symbols with output symbols by name, then by name
+// alone ignoring kind and parent, and finally pairs leftovers with similar names as renames
+func compareAPI(source, output []Symbol) APIParity {
+	var parity APIParity
+	used := make([]bool, len(output))
+	matched := make([]bool, len(source))
+
+	passes := []func(s, o Symbol) bool{
+		func(s, o Symbol) bool {
+			return apiKey(s.Name) == apiKey(o.Name) && kindGroup(s.Kind) == kindGroup(o.Kind) && apiKey(s.Parent) == apiKey(o.Parent)
+		},
+		func(s, o Symbol) bool { return apiKey(s.Name) == apiKey(o.Name) },
+	}
+	for _, match := range passes {
+		for i, s := range source {
+			if matched[i] {
+				continue
+			}
+			for j, o := range output {
+				if !used[j] && match(s, o) {
+					matched[i], used[j] = true, true
+					parity.Matched++
+					break
+				}
+			}
+		}
+	}
+
+	for i, s := range source {
+		if matched[i] {
+			continue
+		}
+		best, bestDistance := -1, 0
+		for j, o := range output {
+			if used[j] || kindGroup(s.Kind) != kindGroup(o.Kind) {
+				continue
+			}
+			a, b := apiKey(s.Name), apiKey(o.Name)
+			d := editDistance(a, b)
+			if d <= max(2, min(len(a), len(b))/3) && (best < 0 || d < bestDistance) {
+				best, bestDistance = j, d
+			}
+		}
+		if best >= 0 {
+			used[best] = true
+			parity.Renamed = append(parity.Renamed, APIRename{From: s, To: output[best]})
+		} else {
+			parity.Missing = append(parity.Missing, s)
+		}
+	}
+
+	for j, o := range output {
+		if !used[j] {
+			parity.Extra = append(parity.Extra, o)
+		}
+	}
+	return parity
+}
+
+// compareFileAPI extracts and compares the public API of a source file and its conversion
+func compareFileAPI(sourceLang, source, targetLang, output string) APIParity {
+	sourceSymbols, err := extractSymbols(sourceLang, source)
+	if err != nil {
+		return APIParity{Error: err.Error()}
+	}
+	outputSymbols, err := extractSymbols(targetLang, output)
+	if err != nil {
+		return APIParity{Error: err.Error()}
+	}
+	return compareAPI(sourceSymbols, outputSymbols)
+}
+
+// CheckAPIParity compares the public API of an original file or directory with its
+// converted counterpart. Directory pairs are matched using the conversion report
+// in the converted directory when present, or by directory and name otherwise.
+func CheckAPIParity(original, converted string) ([]APIParity, error) {
+	pairs, err := sourcePairs(original, converted)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []APIParity
+	for _, pair := range pairs {
+		result := APIParity{Source: pair.original, Output: pair.converted}
+		sourceLang, ok := detectLanguage(pair.original)
+		targetLang, targetOK := detectLanguage(pair.converted)
+		if !ok || !targetOK {
+			result.Error = "cannot tell the language of both files"
+			results = append(results, result)
+			continue
+		}
+
+		source, err := os.ReadFile(pair.original)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", pair.original, err)
+		}
+		output, err := os.ReadFile(pair.converted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", pair.converted, err)
+		}
+
+		parity := compareFileAPI(sourceLang, string(source), targetLang, string(output))
+		parity.Source, parity.Output = pair.original, pair.converted
+		results = append(results, parity)
+	}
+	return results, nil
+}
+
+// sourcePairs pairs every non-test source file under original with its converted file
+func sourcePairs(original, converted string) ([]entryPair, error) {
+	origInfo, err := os.Stat(original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access original %s: %w", original, err)
+	}
+	convInfo, err := os.Stat(converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access converted %s: %w", converted, err)
+	}
+	if origInfo.IsDir() != convInfo.IsDir() {
+		return nil, fmt.Errorf("original and converted must both be files or both be directories")
+	}
+	if !origInfo.IsDir() {
+		return []entryPair{{original, converted}}, nil
+	}
+
+	files, err := listFiles(original)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := listFiles(converted)
+	if err != nil {
+		return nil, err
+	}
+	outputs := reportOutputs(filepath.Join(converted, "conversion-report.json"))
+
+	var pairs []entryPair
+	for _, rel := range files {
+		lang, ok := detectLanguage(rel)
+		if !ok || isTestFile(rel, lang) {
+			continue
+		}
+		match := outputs[filepath.ToSlash(rel)]
+		if match == "" {
+			match = matchConverted(rel, candidates)
+		}
+		if match != "" {
+			pairs = append(pairs, entryPair{filepath.Join(original, rel), filepath.Join(converted, filepath.FromSlash(match))})
+		}
+	}
+	return pairs, nil
+}
+
+// checkFileAPI compares the public API of a converted file with its source and logs any differences
+func (c *Converter) checkFileAPI(job fileJob, source, output string) *APIParity {
+	target := c.target.Name
+	if lang, ok := languages.lookup(target); ok {
+		target = lang.Name
+	}
+	parity := compareFileAPI(job.sourceLang, source, target, output)
+	if !parity.OK() {
+		c.logf("Public API differs for %s -> %s: %s\n", job.inputPath, job.outputPath, parity.Summary())
+		for _, line := range parity.Details() {
+			c.logf("  %s\n", line)
+		}
+	}
+	return &parity
+}
@@ -0,0 +1,155 @@
+package converter
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// symbolNames formats extracted symbols for comparison
+func symbolNames(symbols []Symbol) string {
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.String()
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// TestExtractSymbols tests the public API parsers
+func TestExtractSymbols(t *testing.T) {
+	tests := []struct {
+		lang    string
+		content string
+		want    string
+	}{
+		{
+			"Go",
+			"package users\n\nconst MaxUsers = 10\n\ntype User struct{}\n\ntype store struct{}\n\nfunc NewUser() *User { return nil }\n\nfunc (u *User) Save() error { return nil }\n\nfunc (s *store) Load() {}\n\nfunc helper() {}\n",
+			"constant MaxUsers, function NewUser, method User.Save, type User",
+		},
+		{
+			"Python",
+			"MAX_USERS = 10\n\nclass User:\n    def __init__(self):\n        pass\n\n    def save(self):\n        def inner():\n            pass\n\n    def _cache(self):\n        pass\n\ndef new_user():\n    pass\n\ndef _helper():\n    pass\n",
+			"constant MAX_USERS, function new_user, method User.save, type User",
+		},
+		{
+			"JavaScript",
+			"export function newUser() {}\nexport class User {}\nexport const MAX_USERS = 10;\nexport const load = async (id) => id;\nfunction helper() {}\nexport { helper as assist };\n",
+			"function load, function newUser, type User, variable MAX_USERS, variable assist",
+		},
+		{
+			"JavaScript",
+			"const MAX_USERS = 10;\nclass User {}\nfunction helper() {\n  function inner() {}\n}\n",
+			"constant MAX_USERS, function helper, type User",
+		},
+		{
+			"Java",
+			"public class User {\n    public static final int MAX_USERS = 10;\n    public User() {}\n    public void save() {}\n    private void cache() {}\n    public static User newUser() { return null; }\n}\n",
+			"constant MAX_USERS, method newUser, method save, type User",
+		},
+		{
+			"Rust",
+			"pub const MAX_USERS: u32 = 10;\npub struct User;\nimpl User {\n    pub fn save(&self) {}\n    fn cache(&self) {}\n}\npub(crate) fn internal() {}\n",
+			"constant MAX_USERS, function save, type User",
+		},
+		{
+			"Kotlin",
+			"class User {\n    fun save() {}\n    private fun cache() {}\n}\ninternal fun helper() {}\n",
+			"function save, type User",
+		},
+		{
+			"PHP",
+			"<?php\nclass User {\n    public static function create() {}\n    public function save() {}\n    private function cache() {}\n    protected static function load() {}\n}\n",
+			"function create, function save, type User",
+		},
+		{
+			"C",
+			"static int helper(int x) {\n    return x;\n}\n\nint add(int a, int b) {\n    return a + b;\n}\n",
+			"function add",
+		},
+		{
+			"Swift",
+			"public struct User {}\nfileprivate func cache() {}\npublic static func local() {}\n",
+			"function local, type User",
+		},
+	}
+
+	for _, tt := range tests {
+		symbols, err := extractSymbols(tt.lang, tt.content)
+		if err != nil {
+			t.Errorf("extractSymbols(%s) error = %v", tt.lang, err)
+			continue
+		}
+		if got := symbolNames(symbols); got != tt.want {
+			t.Errorf("extractSymbols(%s) = %s, want %s", tt.lang, got, tt.want)
+		}
+	}
+
+	if _, err := extractSymbols("COBOL", ""); err == nil {
+		t.Errorf("Expected an error for a language without an API parser")
+	}
+}
+
+// TestCompareAPI tests classifying symbols as matched, renamed, missing or extra
+func TestCompareAPI(t *testing.T) {
+	source := []Symbol{
+		{Name: "GetUserByID", Kind: "function"},
+		{Name: "User", Kind: "type"},
+		{Name: "Save", Kind: "method", Parent: "User"},
+		{Name: "DeleteUser", Kind: "function"},
+		{Name: "ParseConfig", Kind: "function"},
+	}
+	output := []Symbol{
+		{Name: "get_user_by_id", Kind: "function"},
+		{Name: "User", Kind: "type"},
+		{Name: "save", Kind: "method", Parent: "User"},
+		{Name: "parse_configs", Kind: "function"},
+		{Name: "helper", Kind: "function"},
+	}
+
+	parity := compareAPI(source, output)
+	if parity.Matched != 3 {
+		t.Errorf("Matched = %d, want 3", parity.Matched)
+	}
+	if got := symbolNames(parity.Missing); got != "function DeleteUser" {
+		t.Errorf("Missing = %s", got)
+	}
+	if len(parity.Renamed) != 1 || parity.Renamed[0].From.Name != "ParseConfig" || parity.Renamed[0].To.Name != "parse_configs" {
+		t.Errorf("Renamed = %+v", parity.Renamed)
+	}
+	if got := symbolNames(parity.Extra); got != "function helper" {
+		t.Errorf("Extra = %s", got)
+	}
+	if parity.OK() {
+		t.Errorf("Expected missing and renamed symbols to fail the check")
+	}
+}
+
+// TestConvertChecksAPI tests that the API check is recorded in the conversion report
+func TestConvertChecksAPI(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"users.go": "package users\n\nfunc NewUser() {}\n\nfunc DeleteUser() {}\n",
+	})
+	defer setupMockGPT("def new_user():\n    pass\n", nil)()
+
+	c := NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetCheckAPI(true)
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	files := c.Report().Files
+	if len(files) != 1 || files[0].API == nil {
+		t.Fatalf("Expected an API check in the report, got %+v", files)
+	}
+	api := files[0].API
+	if api.Matched != 1 || symbolNames(api.Missing) != "function DeleteUser" {
+		t.Errorf("Unexpected API check: %+v", api)
+	}
+}
@@ -0,0 +1,356 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// characterizationTimeout bounds a single run of the source project's test suite
+const characterizationTimeout = 5 * time.Minute
+
+// linkedDirs are dependency directories that the characterisation workspace links
+// to instead of copying, so test suites can still resolve their packages
+var linkedDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// CharacterizationResult records a generated characterisation test and whether it was kept
+type CharacterizationResult struct {
+	Source string `json:"source"`
+	Test   string `json:"test"`
+	Kept   bool   `json:"kept"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// SetCharacterize enables writing characterisation tests for untested source
+// files before conversion. Tests that pass against the original code are
+// converted along with it; the source tree itself is never modified.
+func (c *Converter) SetCharacterize(enabled bool) {
+	c.characterize = enabled
+}
+
+// characterization is a generated test waiting to be confirmed against the original code
+type characterization struct {
+	source  fileJob
+	lang    *Language
+	testRel string
+	code    string
+}
+
+// characterizeSources generates characterisation tests for source files that
+// have none, runs them in a scratch copy of the input tree and returns jobs for
+// the tests that pass. The returned cleanup removes the scratch copy.
+func (c *Converter) characterizeSources(jobs []fileJob) ([]fileJob, func(), error) {
+	cleanup := func() {}
+	pending := untestedSources(jobs)
+	if len(pending) == 0 {
+		return nil, cleanup, nil
+	}
+
+	workspace, err := os.MkdirTemp("", "code-converter-characterize")
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("failed to create characterisation workspace: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(workspace) }
+	if err := c.copyInputs(workspace); err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+
+	var generated []characterization
+	for _, job := range pending {
+		lang, _ := languages.lookup(job.sourceLang)
+		testRel := filepath.FromSlash(expandTestPath(lang, strings.TrimSuffix(filepath.Base(job.relPath), filepath.Ext(job.relPath)), splitDir(filepath.Dir(job.relPath))))
+		if _, err := os.Stat(filepath.Join(workspace, testRel)); err == nil {
+			continue
+		}
+
+		content, err := os.ReadFile(job.inputPath)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("failed to read file %s: %w", job.inputPath, err)
+		}
+		provider, _, err := c.providerFor(job.relPath, job.sourceLang, string(content))
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		redacted, redactions, blocked := c.redact(string(content), job.inputPath)
+		if provider == nil || blocked {
+			c.report.Characterization = append(c.report.Characterization, CharacterizationResult{Source: filepath.ToSlash(job.relPath), Test: filepath.ToSlash(testRel), Reason: "the source may not be sent to the model"})
+			continue
+		}
+		c.logf("Generating characterisation tests for %s\n", job.inputPath)
+		code, err := characterizeUsingLLM(provider, redacted, lang, job.relPath, testRel, secretGuidance(redactions)...)
+		if err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("failed to generate tests for %s: %w", job.inputPath, err)
+		}
+		code = restoreSecrets(code, redactions)
+		generated = append(generated, characterization{source: job, lang: lang, testRel: testRel, code: code})
+	}
+
+	// Each language's suite runs once with every generated test; on failure the
+	// tests are tried one at a time so a single bad test does not sink the rest
+	byLang := map[string][]characterization{}
+	var order []string
+	for _, g := range generated {
+		if _, ok := byLang[g.lang.Name]; !ok {
+			order = append(order, g.lang.Name)
+		}
+		byLang[g.lang.Name] = append(byLang[g.lang.Name], g)
+	}
+
+	// Languages with tests of their own get a baseline run of their suite first
+	tested := map[string]bool{}
+	for _, job := range jobs {
+		if job.test {
+			tested[job.sourceLang] = true
+		}
+	}
+
+	var extra []fileJob
+	for _, name := range order {
+		group := byLang[name]
+		failures, err := c.confirmCharacterizations(workspace, group, tested[name])
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		for _, g := range group {
+			result := CharacterizationResult{Source: filepath.ToSlash(g.source.relPath), Test: filepath.ToSlash(g.testRel), Kept: failures[g.testRel] == ""}
+			if !result.Kept {
+				result.Reason = failures[g.testRel]
+				c.logf("Discarding characterisation tests for %s: they do not pass against the original code\n", g.source.inputPath)
+			} else {
+				extra = append(extra, c.characterizationJob(workspace, g))
+			}
+			c.report.Characterization = append(c.report.Characterization, result)
+		}
+	}
+	return extra, cleanup, nil
+}
+
+// confirmCharacterizations writes a language's generated tests into the workspace,
+// runs its test suite and returns the failure output for every test that was
+// discarded, keyed by test path; passing tests map to an empty string. Tests that
+// pass are left in the workspace so they can be converted. When the project has
+// tests of its own, the suite first runs without the generated ones: if it already
+// fails, the generated tests cannot be confirmed and are all discarded.
+func (c *Converter) confirmCharacterizations(workspace string, group []characterization, baseline bool) (map[string]string, error) {
+	write := func(g characterization) error {
+		path := filepath.Join(workspace, g.testRel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		return os.WriteFile(path, []byte(g.code), 0644)
+	}
+	remove := func(g characterization) {
+		os.Remove(filepath.Join(workspace, g.testRel))
+	}
+	suite := group[0].lang.TestCommand
+
+	failures := map[string]string{}
+	if baseline {
+		result, err := executeCommand(suite, "", workspace, VerifyCase{}, characterizationTimeout, io.Discard)
+		reason := ""
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case result.ExitCode != 0:
+			reason = "the existing test suite already fails without the generated tests:\n" + suiteFailure(result)
+		}
+		if reason != "" {
+			c.logf("Cannot confirm %s characterisation tests: the existing test suite does not pass\n", group[0].lang.Name)
+			for _, g := range group {
+				failures[g.testRel] = reason
+			}
+			return failures, nil
+		}
+	}
+
+	for _, g := range group {
+		if err := write(g); err != nil {
+			return nil, err
+		}
+	}
+	result, err := executeCommand(suite, "", workspace, VerifyCase{}, characterizationTimeout, c.log)
+	if err != nil {
+		for _, g := range group {
+			failures[g.testRel] = err.Error()
+			remove(g)
+		}
+		return failures, nil
+	}
+	if result.ExitCode == 0 {
+		for _, g := range group {
+			failures[g.testRel] = ""
+		}
+		return failures, nil
+	}
+
+	for _, g := range group {
+		remove(g)
+	}
+	for _, g := range group {
+		if err := write(g); err != nil {
+			return nil, err
+		}
+		result, err := executeCommand(suite, "", workspace, VerifyCase{}, characterizationTimeout, io.Discard)
+		switch {
+		case err != nil:
+			failures[g.testRel] = err.Error()
+		case result.ExitCode != 0:
+			failures[g.testRel] = suiteFailure(result)
+		default:
+			failures[g.testRel] = ""
+			continue
+		}
+		remove(g)
+	}
+	return failures, nil
+}
+
+// suiteFailure summarises a failed test suite run, keeping the end of its output where failures are reported
+func suiteFailure(result RunResult) string {
+	const limit = 2000
+	output := strings.TrimSpace(result.Stdout + "\n" + result.Stderr)
+	if len(output) > limit {
+		output = "..." + output[len(output)-limit:]
+	}
+	if output == "" {
+		return fmt.Sprintf("test suite exited with code %d", result.ExitCode)
+	}
+	return output
+}
+
+// characterizationJob creates the conversion job for a confirmed characterisation test
+func (c *Converter) characterizationJob(workspace string, g characterization) fileJob {
+	return fileJob{
+		inputPath:  filepath.Join(workspace, g.testRel),
+		relPath:    g.testRel,
+		outputPath: c.testOutputPath(g.testRel),
+		sourceLang: g.lang.Name,
+		detection:  Detection{Language: g.lang.Name, Confidence: 1, Method: "generated"},
+		convert:    true,
+		test:       true,
+		generated:  true,
+	}
+}
+
+// untestedSources returns the convertible, non-test source files that no test file
+// exercises and whose language has a test framework, test path and test command
+func untestedSources(jobs []fileJob) []fileJob {
+	tested := map[string]bool{}
+	for _, job := range jobs {
+		if job.test {
+			dir := strings.Join(testSubjectDirs(filepath.Dir(job.relPath)), "/")
+			tested[dir+"/"+testSubject(filepath.Base(job.relPath))] = true
+		}
+	}
+
+	var pending []fileJob
+	for _, job := range jobs {
+		if !job.convert || job.test {
+			continue
+		}
+		lang, ok := languages.lookup(job.sourceLang)
+		if !ok || lang.TestFramework == "" || lang.TestPath == "" || len(lang.TestCommand) == 0 {
+			continue
+		}
+		key := strings.Join(splitDir(filepath.Dir(job.relPath)), "/") + "/" + strings.TrimSuffix(filepath.Base(job.relPath), filepath.Ext(job.relPath))
+		if !tested[key] {
+			pending = append(pending, job)
+		}
+	}
+	return pending
+}
+
+// copyInputs copies the inputs into the workspace at their paths relative to the
+// common root of the inputs, so nothing else below that root is copied
+func (c *Converter) copyInputs(workspace string) error {
+	for _, input := range c.inputs {
+		abs, err := filepath.Abs(input)
+		if err != nil {
+			return fmt.Errorf("failed to resolve input path %s: %w", input, err)
+		}
+		rel, err := filepath.Rel(c.inputDir, abs)
+		if err != nil {
+			return fmt.Errorf("failed to compute relative path for %s: %w", input, err)
+		}
+		target := filepath.Join(workspace, rel)
+
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("failed to access input path %s: %w", input, err)
+		}
+		if info.IsDir() {
+			err = copyTree(abs, target)
+		} else if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+			err = copyFile(abs, target)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to copy %s into the characterisation workspace: %w", input, err)
+		}
+	}
+	return nil
+}
+
+// copyTree copies the files under src into dst, linking dependency directories
+// and skipping the other ignored directories
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			if path != src && linkedDirs[d.Name()] {
+				// Overlapping inputs may have linked it already
+				if _, err := os.Lstat(target); err == nil {
+					return filepath.SkipDir
+				}
+				if err := os.Symlink(path, target); err != nil {
+					return fmt.Errorf("failed to link %s: %w", path, err)
+				}
+				return filepath.SkipDir
+			}
+			if path != src && shouldIgnoreDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := copyFile(path, target); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// characterizeUsingLLM asks the model for tests that pin down the current behaviour of source code
+func characterizeUsingLLM(provider Provider, sourceCode string, lang *Language, sourceRel, testRel string, guidance ...string) (string, error) {
+	prompt := fmt.Sprintf("Write characterisation tests for the following %s code using %s. "+
+		"The tests must pin down what the code does today, including edge cases and error handling, so that a rewrite can be checked against them; "+
+		"assert its current behaviour even where it looks wrong, and only test behaviour that is deterministic. "+
+		"The code is in %s and the tests will be saved as %s, both relative to the project root, so import the code accordingly:\n\n%s. Just return the test code, no other text.",
+		lang.Name, lang.TestFramework, filepath.ToSlash(sourceRel), filepath.ToSlash(testRel), sourceCode)
+	if len(guidance) > 0 {
+		prompt += "\n\n" + strings.Join(guidance, "\n")
+	}
+
+	code, err := provider.Generate(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate %s tests: %w", lang.Name, err)
+	}
+	return code, nil
+}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUntestedSources tests that only source files without a matching test are characterised
+func TestUntestedSources(t *testing.T) {
+	jobs := []fileJob{
+		{relPath: filepath.Join("pkg", "parser.go"), sourceLang: "Go", convert: true},
+		{relPath: filepath.Join("pkg", "parser_test.go"), sourceLang: "Go", convert: true, test: true},
+		{relPath: filepath.Join("pkg", "lexer.go"), sourceLang: "Go", convert: true},
+		{relPath: "util.py", sourceLang: "Python", convert: true},
+		{relPath: filepath.Join("tests", "test_util.py"), sourceLang: "Python", convert: true, test: true},
+		{relPath: "notes.txt"},
+	}
+
+	var got []string
+	for _, job := range untestedSources(jobs) {
+		got = append(got, filepath.ToSlash(job.relPath))
+	}
+	if strings.Join(got, ",") != "pkg/lexer.go" {
+		t.Errorf("untestedSources() = %v, want [pkg/lexer.go]", got)
+	}
+}
+
+// TestCharacterize tests that generated tests are kept only when they pass against the original code
+func TestCharacterize(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root := t.TempDir()
+	out := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"go.mod":  "module demo\n\ngo 1.21\n",
+		"calc.go": "package demo\n\nfunc Add(a, b int) int { return a + b }\n",
+		"text.go": "package demo\n\nfunc Shout(s string) string { return s + \"!\" }\n",
+	})
+
+	defer setupMockProvider(func(prompt string) (string, error) {
+		switch {
+		case strings.Contains(prompt, "characterisation") && strings.Contains(prompt, "func Add"):
+			return "package demo\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(2, 3) != 5 {\n\t\tt.Fatal(\"Add\")\n\t}\n}\n", nil
+		case strings.Contains(prompt, "characterisation"):
+			// A test that does not hold for the original code must be discarded
+			return "package demo\n\nimport \"testing\"\n\nfunc TestShout(t *testing.T) {\n\tif Shout(\"a\") != \"A!\" {\n\t\tt.Fatal(\"Shout\")\n\t}\n}\n", nil
+		}
+		return "converted", nil
+	})()
+
+	var log bytes.Buffer
+	c := NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetCharacterize(true)
+	c.SetLogOutput(&log)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v\n%s", err, log.String())
+	}
+
+	results := map[string]bool{}
+	for _, r := range c.Report().Characterization {
+		results[r.Source] = r.Kept
+	}
+	if !results["calc.go"] || results["text.go"] {
+		t.Errorf("Unexpected characterisation results: %+v", c.Report().Characterization)
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "tests", "test_calc.py")); err != nil {
+		t.Errorf("Expected the confirmed test to be converted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "tests", "test_text.py")); !os.IsNotExist(err) {
+		t.Errorf("Expected the failing test to be discarded")
+	}
+	if _, err := os.Stat(filepath.Join(root, "calc_test.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected the source tree to be left untouched")
+	}
+}
+
+// TestCharacterizeBaseline tests that generated tests are not blamed for a suite that already fails
+func TestCharacterizeBaseline(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"go.mod":       "module demo\n\ngo 1.21\n",
+		"calc.go":      "package demo\n\nfunc Add(a, b int) int { return a + b }\n",
+		"calc_test.go": "package demo\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(2, 2) != 5 {\n\t\tt.Fatal(\"Add\")\n\t}\n}\n",
+		"text.go":      "package demo\n\nfunc Shout(s string) string { return s + \"!\" }\n",
+	})
+
+	defer setupMockProvider(func(prompt string) (string, error) {
+		if strings.Contains(prompt, "characterisation") {
+			return "package demo\n\nimport \"testing\"\n\nfunc TestShout(t *testing.T) {\n\tif Shout(\"a\") != \"a!\" {\n\t\tt.Fatal(\"Shout\")\n\t}\n}\n", nil
+		}
+		return "converted", nil
+	})()
+
+	var log bytes.Buffer
+	c := NewConverter(root, t.TempDir(), "python")
+	c.SetScaffold(false)
+	c.SetCharacterize(true)
+	c.SetLogOutput(&log)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v\n%s", err, log.String())
+	}
+
+	results := c.Report().Characterization
+	if len(results) != 1 || results[0].Kept || !strings.Contains(results[0].Reason, "already fails") {
+		t.Errorf("Expected the generated test to be discarded because of the existing suite: %+v", results)
+	}
+}
+
+// TestCopyInputs tests that the characterisation workspace holds only the inputs
+func TestCopyInputs(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"a/x.py":         "x = 1\n",
+		"a/unrelated.py": "y = 2\n",
+		"b/lib/y.py":     "z = 3\n",
+		"b/lib/more.py":  "w = 4\n",
+		"other.txt":      "not an input\n",
+	})
+
+	c := NewMultiConverter([]string{filepath.Join(root, "a", "x.py"), filepath.Join(root, "b", "lib")}, t.TempDir(), "go")
+	c.inputDir = root
+	workspace := t.TempDir()
+	if err := c.copyInputs(workspace); err != nil {
+		t.Fatalf("copyInputs() error = %v", err)
+	}
+
+	var copied []string
+	filepath.WalkDir(workspace, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(workspace, path)
+			copied = append(copied, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if strings.Join(copied, ",") != "a/x.py,b/lib/more.py,b/lib/y.py" {
+		t.Errorf("Copied %v, want only the inputs at their relative paths", copied)
+	}
+}
@@ -0,0 +1,335 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// compareReportName is the file each candidate tree's conversion report is written to
+const compareReportName = "conversion-report.json"
+
+// CompareCandidate is one configuration's conversion of a source file
+type CompareCandidate struct {
+	Config     string            `json:"config"`
+	Output     string            `json:"output,omitempty"`
+	Action     string            `json:"action"`
+	Validation *ValidationResult `json:"validation,omitempty"`
+	Size       int               `json:"size"`
+	Lines      int               `json:"lines"`
+	Usage      Usage             `json:"usage"`
+	Cost       float64           `json:"cost_usd"`
+	Unpriced   []string          `json:"unpriced_models,omitempty"`
+
+	// Added and Removed count the lines that differ from the baseline, the first
+	// configuration's output, and Diff shows them
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// FileComparison lists every configuration's conversion of one source file
+type FileComparison struct {
+	Source     string             `json:"source"`
+	Candidates []CompareCandidate `json:"candidates"`
+}
+
+// CompareSummary totals one configuration's conversion of the whole input
+type CompareSummary struct {
+	Config            string   `json:"config"`
+	Provider          string   `json:"provider"`
+	OutputDir         string   `json:"output_dir"`
+	Converted         int      `json:"converted"`
+	Blocked           int      `json:"blocked"`
+	ValidationPassed  int      `json:"validation_passed"`
+	ValidationChecked int      `json:"validation_checked"`
+	Size              int      `json:"size"`
+	Usage             Usage    `json:"usage"`
+	Cost              float64  `json:"cost_usd"`
+	Unpriced          []string `json:"unpriced_models,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+// CompareReport holds the per-configuration totals and per-file comparison of a compare run
+type CompareReport struct {
+	Configs []CompareSummary `json:"configs"`
+	Files   []FileComparison `json:"files"`
+}
+
+// Comparer converts the same input with several configurations into side-by-side
+// output trees and compares the results file by file
+type Comparer struct {
+	inputs     []string
+	outputDir  string
+	targetLang string
+	sourceLang string
+	layout     Layout
+	configs    []EvalConfig
+	prices     map[string]Price
+	log        io.Writer
+
+	// policy and blockOnSecrets restrict what every configuration may send, as for Converter
+	policy         *EgressPolicy
+	blockOnSecrets bool
+}
+
+// NewComparer creates a Comparer that writes one output tree per configuration under outputDir
+func NewComparer(inputs []string, outputDir, targetLang string) *Comparer {
+	return &Comparer{
+		inputs:     inputs,
+		outputDir:  outputDir,
+		targetLang: targetLang,
+		layout:     LayoutMirror,
+		prices:     defaultPrices,
+		log:        os.Stdout,
+	}
+}
+
+// SetConfigs sets the configurations to compare; the first one is the baseline that the others are diffed against
+func (cmp *Comparer) SetConfigs(configs []EvalConfig) {
+	cmp.configs = configs
+}
+
+// SetPrices adds or overrides model prices used to estimate cost
+func (cmp *Comparer) SetPrices(prices map[string]Price) {
+	cmp.prices = mergePrices(cmp.prices, prices)
+}
+
+// SetSourceLanguage overrides source language detection for every file
+func (cmp *Comparer) SetSourceLanguage(lang string) {
+	cmp.sourceLang = lang
+}
+
+// SetLayout controls how converted files are arranged in each output tree
+func (cmp *Comparer) SetLayout(layout Layout) {
+	cmp.layout = layout
+}
+
+// SetEgressPolicy restricts which providers each file may be sent to, in every configuration
+func (cmp *Comparer) SetEgressPolicy(policy *EgressPolicy) {
+	cmp.policy = policy
+}
+
+// SetBlockOnSecrets refuses to send files that contain secrets to any configuration's model
+func (cmp *Comparer) SetBlockOnSecrets(enabled bool) {
+	cmp.blockOnSecrets = enabled
+}
+
+// SetLogOutput redirects progress messages, which go to stdout by default
+func (cmp *Comparer) SetLogOutput(w io.Writer) {
+	cmp.log = w
+}
+
+// Compare runs every configuration and builds the comparison. A configuration whose
+// conversion fails is reported with its error, and the others still run.
+func (cmp *Comparer) Compare() (CompareReport, error) {
+	if len(cmp.configs) < 2 {
+		return CompareReport{}, fmt.Errorf("at least two configurations are needed to compare, got %d", len(cmp.configs))
+	}
+	dirs := map[string]string{}
+	for _, config := range cmp.configs {
+		if _, err := lookupProvider(config.Provider); err != nil {
+			return CompareReport{}, fmt.Errorf("configuration %s: %w", config.Name, err)
+		}
+		dir := pathSafe(config.Name)
+		if other, ok := dirs[dir]; ok {
+			return CompareReport{}, fmt.Errorf("configurations %s and %s would share the output directory %s", other, config.Name, dir)
+		}
+		dirs[dir] = config.Name
+	}
+
+	var report CompareReport
+	index := map[string]int{}
+	for i, config := range cmp.configs {
+		dir := pathSafe(config.Name)
+		summary := CompareSummary{Config: config.Name, Provider: config.Provider, OutputDir: dir}
+		fmt.Fprintf(cmp.log, "Converting with %s into %s\n", config.Name, filepath.Join(cmp.outputDir, dir))
+
+		c := NewMultiConverter(cmp.inputs, filepath.Join(cmp.outputDir, dir), cmp.targetLang)
+		c.SetScaffold(false)
+		c.SetValidate(true)
+		c.SetSourceLanguage(cmp.sourceLang)
+		c.SetLayout(cmp.layout)
+		c.SetProvider(config.Provider)
+		c.SetEgressPolicy(cmp.policy)
+		c.SetBlockOnSecrets(cmp.blockOnSecrets)
+		c.SetModelParams(config.Params)
+		c.SetReportPath(filepath.Join(cmp.outputDir, dir, compareReportName))
+		c.SetLogOutput(cmp.log)
+		for _, g := range config.Guidance {
+			c.AddGuidance(g)
+		}
+		if err := c.Convert(); err != nil {
+			summary.Error = err.Error()
+			fmt.Fprintf(cmp.log, "Conversion with %s failed: %v\n", config.Name, err)
+		}
+
+		conversion := c.Report()
+		summary.Usage, summary.Cost, summary.Unpriced = estimateCost(cmp.prices, conversion.Usage)
+		for _, f := range conversion.Files {
+			if f.Action == actionCopied {
+				continue
+			}
+			candidate := cmp.candidate(config, dir, f)
+			switch candidate.Action {
+			case actionConverted:
+				summary.Converted++
+			case actionBlocked:
+				summary.Blocked++
+			}
+			if v := candidate.Validation; v != nil && !v.Skipped {
+				summary.ValidationChecked++
+				if v.Passed {
+					summary.ValidationPassed++
+				}
+			}
+			summary.Size += candidate.Size
+
+			j, ok := index[f.Source]
+			if !ok {
+				j = len(report.Files)
+				index[f.Source] = j
+				report.Files = append(report.Files, FileComparison{Source: f.Source})
+			}
+			// Earlier configurations that did not produce this file get an empty candidate
+			for len(report.Files[j].Candidates) < i {
+				report.Files[j].Candidates = append(report.Files[j].Candidates, CompareCandidate{Config: cmp.configs[len(report.Files[j].Candidates)].Name})
+			}
+			report.Files[j].Candidates = append(report.Files[j].Candidates, candidate)
+		}
+		report.Configs = append(report.Configs, summary)
+	}
+
+	for j := range report.Files {
+		file := &report.Files[j]
+		for len(file.Candidates) < len(cmp.configs) {
+			file.Candidates = append(file.Candidates, CompareCandidate{Config: cmp.configs[len(file.Candidates)].Name})
+		}
+		cmp.diffCandidates(file)
+	}
+	return report, nil
+}
+
+// candidate describes a configuration's output for one file of its conversion report
+func (cmp *Comparer) candidate(config EvalConfig, dir string, f FileReport) CompareCandidate {
+	candidate := CompareCandidate{Config: config.Name, Action: f.Action, Validation: f.Validation}
+	candidate.Usage, candidate.Cost, candidate.Unpriced = estimateCost(cmp.prices, f.Usage)
+	if f.Output == "" {
+		return candidate
+	}
+	candidate.Output = filepath.ToSlash(filepath.Join(dir, filepath.FromSlash(f.Output)))
+	if content, err := os.ReadFile(filepath.Join(cmp.outputDir, filepath.FromSlash(candidate.Output))); err == nil {
+		candidate.Size = len(content)
+		candidate.Lines = len(splitLines(string(content)))
+	}
+	return candidate
+}
+
+// diffCandidates diffs every candidate's output against the baseline's
+func (cmp *Comparer) diffCandidates(file *FileComparison) {
+	baseline := file.Candidates[0]
+	if baseline.Output == "" {
+		return
+	}
+	baseContent, err := os.ReadFile(filepath.Join(cmp.outputDir, filepath.FromSlash(baseline.Output)))
+	if err != nil {
+		return
+	}
+	for i := 1; i < len(file.Candidates); i++ {
+		candidate := &file.Candidates[i]
+		if candidate.Output == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(cmp.outputDir, filepath.FromSlash(candidate.Output)))
+		if err != nil {
+			continue
+		}
+		for _, op := range diffLines(splitLines(string(baseContent)), splitLines(string(content))) {
+			switch op.kind {
+			case '+':
+				candidate.Added++
+			case '-':
+				candidate.Removed++
+			}
+		}
+		candidate.Diff = unifiedDiff(baseline.Output, candidate.Output, string(baseContent), string(content))
+	}
+}
+
+// WriteTable prints one row per file and configuration followed by the totals of each configuration
+func (r CompareReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tCONFIG\tACTION\tVALID\tSIZE\tTOKENS\tCOST\tDIFF")
+	for _, f := range r.Files {
+		for i, c := range f.Candidates {
+			action := c.Action
+			if action == "" {
+				action = "missing"
+			}
+			diff := "baseline"
+			if i > 0 {
+				diff = describeChange(c)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+				f.Source, c.Config, action, describeValidation(c.Validation), c.Size, c.Usage.TotalTokens, formatCost(c.Cost, c.Unpriced), diff)
+		}
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "CONFIG\tPROVIDER\tCONVERTED\tVALID\tSIZE\tTOKENS\tCOST\tERROR")
+	for _, s := range r.Configs {
+		valid := "-"
+		if s.ValidationChecked > 0 {
+			valid = fmt.Sprintf("%d/%d", s.ValidationPassed, s.ValidationChecked)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%d\t%s\t%s\n",
+			s.Config, s.Provider, s.Converted, valid, s.Size, s.Usage.TotalTokens, formatCost(s.Cost, s.Unpriced), s.Error)
+	}
+	return tw.Flush()
+}
+
+// describeValidation summarises a validation result for a table cell
+func describeValidation(v *ValidationResult) string {
+	switch {
+	case v == nil || v.Skipped:
+		return "-"
+	case v.Passed:
+		return "pass"
+	default:
+		return "FAIL"
+	}
+}
+
+// describeChange summarises how a candidate differs from the baseline
+func describeChange(c CompareCandidate) string {
+	switch {
+	case c.Output == "":
+		return "-"
+	case c.Added == 0 && c.Removed == 0 && c.Diff == "":
+		return "same"
+	default:
+		return fmt.Sprintf("+%d -%d", c.Added, c.Removed)
+	}
+}
+
+// formatCost shows a cost in dollars, or "?" when a model had no price
+func formatCost(cost float64, unpriced []string) string {
+	if len(unpriced) > 0 {
+		return "?"
+	}
+	return fmt.Sprintf("$%.4f", cost)
+}
+
+// WriteCompareReport saves a comparison as JSON
+func WriteCompareReport(report CompareReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode compare report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write compare report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// SetConcurrency converts up to n files at once; 1 or less converts them one by one.
+// The report lists files in input order either way.
+func (c *Converter) SetConcurrency(n int) {
+	c.concurrency = n
+}
+
+// processJobs processes every job, on a pool of workers when concurrency is enabled.
+// After a failure no new jobs are started, and the error of the earliest failed job
+// is returned once the running ones finish.
+func (c *Converter) processJobs(jobs []fileJob) error {
+	workers := min(c.concurrency, len(jobs))
+	if workers <= 1 {
+		for _, job := range jobs {
+			if err := c.processJob(job); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	log := c.log
+	c.log = &syncWriter{w: log}
+	defer func() { c.log = log }()
+
+	errs := make([]error, len(jobs))
+	var failed atomic.Bool
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if errs[i] = c.processJob(jobs[i]); errs[i] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		if failed.Load() {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	c.sortResults(jobs)
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortResults puts report entries and converted files back in job order after
+// workers finished them in whatever order they ran
+func (c *Converter) sortResults(jobs []fileJob) {
+	sources := make(map[string]int, len(jobs))
+	inputs := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		sources[filepath.ToSlash(job.relPath)] = i
+		inputs[job.inputPath] = i
+	}
+	sort.SliceStable(c.report.Files, func(i, j int) bool {
+		return sources[c.report.Files[i].Source] < sources[c.report.Files[j].Source]
+	})
+	sort.SliceStable(c.converted, func(i, j int) bool {
+		return inputs[c.converted[i].sourcePath] < inputs[c.converted[j].sourcePath]
+	})
+}
+
+// syncWriter serialises writes from concurrent workers
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
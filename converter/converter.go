@@ -3,113 +3,254 @@ package converter
 import (
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Converter handles the code conversion process
 type Converter struct {
+	inputs     []string
 	inputDir   string
 	outputDir  string
 	targetLang string
+	target     TargetSpec
+	sourceLang string
+	scaffold   bool
+	validate   bool
+	layout     Layout
+	collisions CollisionPolicy
+	log        io.Writer
+	reportPath string
+	report     Report
+	converted  []convertedFile
+
+	writeInPlace   bool
+	characterize   bool
+	checkAPI       bool
+	blockOnSecrets bool
+	provider       string
+	policy         *EgressPolicy
+	transcript     *Transcript
+	guidance       []string
+	fileUsage      map[string]map[string]*ModelUsage
+	samples        int
+	reviewer       string
+	reviewFix      bool
+	fallback       []string
+	fallbackOn     []string
+	params         ModelParams
+	deterministic  bool
+	observer       Observer
+	concurrency    int
+	include        []string
+	exclude        []string
+
+	// mu guards the report, usage and converted files while files are converted concurrently
+	mu sync.Mutex
+}
+
+// convertedFile records a source file that was translated into the output tree
+type convertedFile struct {
+	sourcePath string
+	outputPath string
+	sourceLang string
+	source     string
+	output     string
+	// test marks converted test files
+	test bool
 }
 
 // NewConverter creates a new Converter instance
 func NewConverter(inputDir, outputDir, targetLang string) *Converter {
+	return NewMultiConverter([]string{inputDir}, outputDir, targetLang)
+}
+
+// NewMultiConverter creates a Converter for several files and directories, whose
+// relative paths are mirrored under outputDir from their common root
+func NewMultiConverter(inputs []string, outputDir, targetLang string) *Converter {
+	// targetLang may carry a version, platform and modifiers, e.g. "typescript@5 strict"
+	target := ParseTargetSpec(targetLang)
+	if outputDir != "" {
+		// Cleaned so that output paths can be compared against it, e.g. "./out/" and "out"
+		outputDir = filepath.Clean(outputDir)
+	}
 	return &Converter{
-		inputDir:   inputDir,
+		inputs:     inputs,
 		outputDir:  outputDir,
-		targetLang: targetLang,
+		targetLang: target.Name,
+		target:     target,
+		scaffold:   true,
+		layout:     LayoutMirror,
+		collisions: CollisionError,
+		log:        os.Stdout,
+		observer:   NopObserver{},
+		report:     Report{TargetLanguage: target.Describe()},
 	}
 }
 
-// Convert performs the full conversion process
-func (c *Converter) Convert() error {
-	return c.processDirectory(c.inputDir, c.outputDir)
+// SetScaffold controls whether project manifests and a README are generated after conversion
+func (c *Converter) SetScaffold(enabled bool) {
+	c.scaffold = enabled
+}
+
+// SetLogOutput redirects progress messages, which go to stdout by default
+func (c *Converter) SetLogOutput(w io.Writer) {
+	c.log = w
 }
 
-// processDirectory recursively processes all files in a directory
-func (c *Converter) processDirectory(inputPath, outputPath string) error {
+// logf writes a progress message to the converter's log output
+func (c *Converter) logf(format string, args ...interface{}) {
+	fmt.Fprintf(c.log, format, args...)
+}
+
+// Convert performs the full conversion process
+func (c *Converter) Convert() error {
+	// Plan every output path up front so collisions are reported before anything is written
+	jobs, err := c.plan()
+	if err != nil {
+		return err
+	}
+	
+	if c.characterize {
+		generated, cleanup, err := c.characterizeSources(jobs)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		
+		jobs = append(jobs, generated...)
+		if err := c.resolveCollisions(jobs); err != nil {
+			return err
+		}
+		linkTestSubjects(jobs)
+	}
+	
 	// Create the output directory if it doesn't exist
-	if err := os.MkdirAll(outputPath, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %s: %w", outputPath, err)
+	if err := os.MkdirAll(c.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", c.outputDir, err)
 	}
 	
-	entries, err := os.ReadDir(inputPath)
-	if err != nil {
-		return fmt.Errorf("failed to read directory %s: %w", inputPath, err)
+	if params := c.requestParams(); params != (ModelParams{}) {
+		c.report.Params = &params
 	}
 	
-	for _, entry := range entries {
-		inPath := filepath.Join(inputPath, entry.Name())
-		outPath := filepath.Join(outputPath, entry.Name())
-		
-		if entry.IsDir() {
-			// Skip common directories to ignore
-			if shouldIgnoreDir(entry.Name()) {
-				fmt.Printf("Skipping directory: %s\n", inPath)
-				continue
-			}
-			
-			// Process subdirectory recursively
-			if err := c.processDirectory(inPath, outPath); err != nil {
-				return err
-			}
-		} else {
-			// Process file
-			if err := c.processFile(inPath, outPath, entry); err != nil {
-				return err
-			}
+	if err := c.processJobs(jobs); err != nil {
+		return err
+	}
+	
+	if err := c.writePackageMarkers(); err != nil {
+		return err
+	}
+
+	if c.scaffold {
+		if err := c.writeScaffold(); err != nil {
+			return err
 		}
 	}
 	
-	return nil
+	return c.writeReport(actionConverted, actionCopied)
 }
 
 // processFile converts a single file from source to target language
-func (c *Converter) processFile(inputPath, outputPath string, info fs.DirEntry) error {
-	// Check if this file should be processed based on extension
-	srcLang, shouldProcess := detectLanguage(inputPath)
-	if !shouldProcess {
-		// Just copy the file if we're not converting it
-		return copyFile(inputPath, outputPath)
+func (c *Converter) processFile(job fileJob) error {
+	if err := os.MkdirAll(filepath.Dir(job.outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", filepath.Dir(job.outputPath), err)
 	}
 	
-	fmt.Printf("Converting %s from %s to %s\n", inputPath, srcLang, c.targetLang)
+	if !job.convert {
+		// Just copy the file if we're not converting it
+		if err := copyFile(job.inputPath, job.outputPath); err != nil {
+			return err
+		}
+		c.addFile(c.newFileReport(job, actionCopied))
+		return nil
+	}
 	
 	// Read the source file
-	content, err := os.ReadFile(inputPath)
+	content, err := os.ReadFile(job.inputPath)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", inputPath, err)
+		return fmt.Errorf("failed to read file %s: %w", job.inputPath, err)
 	}
 	
-	// Convert the code
-	convertedCode, newExt, err := c.convertCode(string(content), srcLang, inputPath)
+	entry := c.newFileReport(job, actionConverted)
+	entry.From = describeSource(job.sourceLang, detectDialect(job.sourceLang, string(content)))
+	entry.To = c.target.Describe()
+	c.logf("Converting %s from %s to %s (detected by %s, confidence %.2f)\n",
+		job.inputPath, entry.From, entry.To, job.detection.Method, job.detection.Confidence)
+	
+	guidance := c.layoutGuidance(job.relPath)
+	if job.test {
+		// Tests share the package of the code they exercise, not of their own directory
+		dirs := testSubjectDirs(filepath.Dir(job.relPath))
+		guidance = append(c.layoutGuidance(filepath.Join(append(dirs, filepath.Base(job.relPath))...)), c.testGuidance(job)...)
+	}
+	
+	provider, violation, err := c.providerFor(job.relPath, job.sourceLang, string(content))
 	if err != nil {
-		return fmt.Errorf("failed to convert %s: %w", inputPath, err)
+		return err
+	}
+	entry.Egress = violation
+	redacted, redactions, blocked := c.redact(string(content), job.inputPath)
+	entry.Redactions = redactions
+	if provider == nil || blocked {
+		entry.Action, entry.Output = actionBlocked, ""
+		c.addFile(entry)
+		return nil
 	}
 	
-	// Update the output path with the new file extension if needed
-	if newExt != "" {
-		outputPath = changeExtension(outputPath, newExt)
+	// Convert the code
+	guidance = append(guidance, secretGuidance(redactions)...)
+	convertedCode, err := c.convertWithFallback(&entry, provider, job, redacted, guidance...)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s: %w", job.inputPath, err)
+	}
+	if c.reviewer != "" {
+		convertedCode, entry.Review = c.reviewFile(job, redacted, convertedCode, guidance...)
 	}
+	convertedCode = restoreSecrets(convertedCode, redactions)
+	c.warnUnrestored(job.outputPath, redactions)
+	entry.Usage = c.usageFor(job.relPath)
 	
 	// Write the converted code to the output file
-	if err := os.WriteFile(outputPath, []byte(convertedCode), 0644); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	if err := os.WriteFile(job.outputPath, []byte(convertedCode), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", job.outputPath, err)
 	}
 	
+	c.mu.Lock()
+	c.converted = append(c.converted, convertedFile{
+		sourcePath: job.inputPath,
+		outputPath: job.outputPath,
+		sourceLang: job.sourceLang,
+		source:     string(content),
+		output:     convertedCode,
+		test:       job.test,
+	})
+	c.mu.Unlock()
+	
+	if c.validate {
+		result := c.validateFile(job.outputPath)
+		entry.Validation = &result
+		if !result.Passed && !result.Skipped {
+			c.logf("Validation failed for %s (%s):\n%s\n", job.outputPath, result.Tool, result.Output)
+		}
+	}
+	if c.checkAPI && !job.test {
+		entry.API = c.checkFileAPI(job, string(content), convertedCode)
+	}
+	c.addFile(entry)
+	
 	return nil
 }
 
 // convertCode translates code from one language to another
-func (c *Converter) convertCode(sourceCode, sourceLang, filePath string) (string, string, error) {
+func (c *Converter) convertCode(provider Provider, sourceCode, sourceLang, filePath string, guidance ...string) (string, string, error) {
 	// Get the appropriate file extension for the target language
 	newExt := getTargetExtension(c.targetLang)
-
-	convertedCode, err := convertUsingLLM(sourceCode, sourceLang, c.targetLang)
+	
+	sourceLang = describeSource(sourceLang, detectDialect(sourceLang, sourceCode))
+	convertedCode, err := convertUsingLLM(provider, sourceCode, sourceLang, c.targetLang, c.promptGuidance(guidance...)...)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to convert %s: %w", filePath, err)
 	}
@@ -117,59 +258,90 @@ func (c *Converter) convertCode(sourceCode, sourceLang, filePath string) (string
 	return convertedCode, newExt, nil
 }
 
-// ConvertFile converts a single file from source to target language
+// promptGuidance prepends the target language's guidance and its version, platform
+// and modifier instructions to extra, steering the model towards idiomatic target code
+func (c *Converter) promptGuidance(extra ...string) []string {
+	var guidance []string
+	if lang, ok := languages.lookup(c.targetLang); ok && lang.Guidance != "" {
+		guidance = append(guidance, lang.Guidance)
+	}
+	configured := append(c.target.guidance(), c.guidance...)
+	if c.deterministic {
+		configured = stableGuidance(configured)
+	}
+	guidance = append(guidance, configured...)
+	return append(guidance, extra...)
+}
+
+// AddGuidance adds an instruction to every conversion prompt
+func (c *Converter) AddGuidance(text string) {
+	c.guidance = append(c.guidance, text)
+}
+
+// ConvertFile converts a single file from source to target language, writing it
+// directly into outputDir
 func ConvertFile(filePath, outputDir, targetLang string) error {
-    _, fileName := filepath.Split(filePath)
-    outputPath := filepath.Join(outputDir, fileName)
-    
-    // Create output directory if it doesn't exist
-    if err := os.MkdirAll(outputDir, 0755); err != nil {
-        return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
-    }
-    
-    // Get source language
-    srcLang, shouldProcess := detectLanguage(filePath)
-    if !shouldProcess {
-        // Just copy the file if we're not converting it
-        return copyFile(filePath, outputPath)
-    }
-    
-    fmt.Printf("Converting %s from %s to %s\n", filePath, srcLang, targetLang)
-    
-    // Read the source file
-    content, err := os.ReadFile(filePath)
-    if err != nil {
-        return fmt.Errorf("failed to read file %s: %w", filePath, err)
-    }
-    
-    // Create a temporary converter just for this file
-    c := NewConverter(filePath, outputDir, targetLang)
-    
-    // Convert the code
-    convertedCode, newExt, err := c.convertCode(string(content), srcLang, filePath)
-    if err != nil {
-        return fmt.Errorf("failed to convert %s: %w", filePath, err)
-    }
-    
-    // Update the output path with the new file extension if needed
-    if newExt != "" {
-        outputPath = changeExtension(outputPath, newExt)
-    }
-    
-    // Write the converted code to the output file
-    if err := os.WriteFile(outputPath, []byte(convertedCode), 0644); err != nil {
-        return fmt.Errorf("failed to write file %s: %w", outputPath, err)
-    }
-    
-    return nil
+	c := NewConverter(filePath, outputDir, targetLang)
+	c.SetScaffold(false)
+	return c.Convert()
 }
 
-func convertUsingLLM(sourceCode, sourceLang, targetLang string) (string, error) {
+// ConvertStream converts source code read from r and writes the result to w. If
+// sourceLang is empty the language is detected from the content.
+func (c *Converter) ConvertStream(r io.Reader, w io.Writer, sourceLang string) error {
+	if _, err := ResolveLanguage(c.targetLang); err != nil {
+		return fmt.Errorf("target language: %w", err)
+	}
+	
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read source: %w", err)
+	}
+	
+	if sourceLang == "" {
+		det := detectLanguageFromContent(string(content))
+		if det.Language == "" {
+			return fmt.Errorf("could not detect the source language from the content; set it with -from")
+		}
+		sourceLang = det.Language
+		c.logf("Detected source language %s (%s, confidence %.2f)\n", sourceLang, det.Method, det.Confidence)
+	}
+	
+	provider, _, err := c.providerFor("", sourceLang, string(content))
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("refusing to send stdin: the egress policy does not allow it")
+	}
+	redacted, redactions, blocked := c.redact(string(content), "stdin")
+	if blocked {
+		return fmt.Errorf("refusing to send stdin: it contains %d secrets", len(redactions))
+	}
+	
+	c.logf("Converting stdin from %s to %s\n", sourceLang, c.targetLang)
+	convertedCode, _, err := c.convertCode(provider, redacted, sourceLang, "stdin", secretGuidance(redactions)...)
+	if err != nil {
+		return err
+	}
+	convertedCode = restoreSecrets(convertedCode, redactions)
+	c.warnUnrestored("stdin", redactions)
+	
+	if _, err := io.WriteString(w, convertedCode); err != nil {
+		return fmt.Errorf("failed to write converted code: %w", err)
+	}
+	return nil
+}
+
+func convertUsingLLM(provider Provider, sourceCode, sourceLang, targetLang string, guidance ...string) (string, error) {
 	prompt := fmt.Sprintf("Convert the following %s code to %s:\n\n%s. Just return the converted code, no other text.", sourceLang, targetLang, sourceCode)
+	if len(guidance) > 0 {
+		prompt += "\n\n" + strings.Join(guidance, "\n")
+	}
 
-	convertedCode, err := GenerateText(prompt)
+	convertedCode, err := provider.Generate(prompt)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert %s: %w", sourceCode, err)
+		return "", fmt.Errorf("failed to convert %s code: %w", sourceLang, err)
 	}
 	
 
@@ -196,53 +368,17 @@ func shouldIgnoreDir(dirName string) bool {
 func detectLanguage(filePath string) (string, bool) {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	
-	// Map of file extensions to language names
-	langMap := map[string]string{
-		".go":   "Go",
-		".js":   "JavaScript",
-		".ts":   "TypeScript",
-		".py":   "Python",
-		".java": "Java",
-		".c":    "C",
-		".cpp":  "C++",
-		".cs":   "C#",
-		".rb":   "Ruby",
-		".php":  "PHP",
-		".rs":   "Rust",
-		".swift":"Swift",
-		".kt":   "Kotlin",
+	// The first language registered for an extension is its default
+	if langs := languages.byExtension[ext]; len(langs) > 0 {
+		return langs[0].Name, true
 	}
-	
-	lang, ok := langMap[ext]
-	return lang, ok
+	return "", false
 }
 
 // getTargetExtension returns the file extension for the target language
 func getTargetExtension(targetLang string) string {
-	// Map of language names to file extensions
-	extMap := map[string]string{
-		"go":         ".go",
-		"golang":     ".go",
-		"javascript": ".js",
-		"typescript": ".ts",
-		"python":     ".py",
-		"java":       ".java",
-		"c":          ".c",
-		"c++":        ".cpp",
-		"csharp":     ".cs",
-		"c#":         ".cs",
-		"ruby":       ".rb",
-		"php":        ".php",
-		"rust":       ".rs",
-		"swift":      ".swift",
-		"kotlin":     ".kt",
-	}
-	
-	// Normalize target language to lowercase
-	normalizedLang := strings.ToLower(targetLang)
-	
-	if ext, ok := extMap[normalizedLang]; ok {
-		return ext
+	if lang, ok := languages.lookup(targetLang); ok {
+		return lang.Extension()
 	}
 	return ""
 }
@@ -1,36 +1,41 @@
 package converter
 
 import (
+	"encoding/json"
+	"flag"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 )
 
-// Mock the GenerateText function to avoid actual API calls during tests
-var originalGenerateText func(string) (string, error)
+// mockProvider stands in for the default provider to avoid actual API calls during tests
+type mockProvider func(prompt string) (string, error)
 
-func init() {
-	// Save the original function
-	originalGenerateText = GenerateText
-}
+func (mockProvider) Name() string { return defaultProviderName }
 
-// setupMockGPT sets up the mock function for GenerateText
-func setupMockGPT(mockResponse string, mockError error) func() {
-	// Save the original function
-	originalGenerateText := GenerateText
-	
-	// Replace with mock
-	GenerateText = func(prompt string) (string, error) {
-		return mockResponse, mockError
-	}
+func (m mockProvider) Generate(prompt string) (string, error) { return m(prompt) }
+
+// setupMockProvider registers generate as the default provider
+func setupMockProvider(generate func(prompt string) (string, error)) func() {
+	// Save the original provider
+	original := providers[defaultProviderName]
+	RegisterProvider(mockProvider(generate))
 
 	// Return a cleanup function to restore the original
 	return func() {
-		GenerateText = originalGenerateText
+		RegisterProvider(original)
 	}
 }
 
+// setupMockGPT makes the default provider answer every prompt with the same response
+func setupMockGPT(mockResponse string, mockError error) func() {
+	return setupMockProvider(func(prompt string) (string, error) {
+		return mockResponse, mockError
+	})
+}
+
 // TestFileExtensionConversion tests if file extensions are properly changed based on target language
 func TestFileExtensionConversion(t *testing.T) {
 	tests := []struct {
@@ -131,7 +136,7 @@ func TestConvertCode(t *testing.T) {
 	converter := NewConverter("input", "output", targetLang)
 
 	// Test conversion
-	convertedCode, newExt, err := converter.convertCode(sourceCode, sourceLang, filePath)
+	convertedCode, newExt, err := converter.convertCode(defaultProvider(), sourceCode, sourceLang, filePath)
 	
 	// Assertions
 	if err != nil {
@@ -281,4 +286,138 @@ func TestIgnoreDirectories(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+// TestConvertReplaysTranscript reproduces a recorded run offline from its transcript
+// and compares the output tree with the golden files recorded alongside it
+func TestConvertReplaysTranscript(t *testing.T) {
+	replay, err := LoadReplayProvider(filepath.Join("testdata", "replay", "transcript.jsonl"))
+	if err != nil {
+		t.Fatalf("LoadReplayProvider() error = %v", err)
+	}
+	RegisterProvider(replay)
+	defer delete(providers, replay.Name())
+
+	out := t.TempDir()
+	c := NewConverter(filepath.Join("testdata", "replay", "input"), out, "python")
+	c.SetScaffold(false)
+	c.SetProvider(replay.Name())
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	checkGolden(t, out, filepath.Join("testdata", "replay", "expected"))
+}
+
+// update rewrites the golden files in testdata with the current output: go test ./converter -update
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// goldenCase holds the options of a golden test case, read from its case.json
+type goldenCase struct {
+	Lang     string `json:"lang"`
+	From     string `json:"from,omitempty"`
+	Layout   string `json:"layout,omitempty"`
+	Scaffold bool   `json:"scaffold,omitempty"`
+}
+
+// TestGolden converts every fixture tree in testdata/golden with a fake provider
+// serving the case's fixtures.json and compares the output with its expected tree
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil || len(dirs) == 0 {
+		t.Fatalf("No golden cases found: %v", err)
+	}
+
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dir, "case.json"))
+			if err != nil {
+				t.Fatalf("Failed to read case: %v", err)
+			}
+			var gc goldenCase
+			if err := json.Unmarshal(data, &gc); err != nil {
+				t.Fatalf("Failed to parse case: %v", err)
+			}
+			if gc.Layout == "" {
+				gc.Layout = string(LayoutMirror)
+			}
+			layout, err := ParseLayout(gc.Layout)
+			if err != nil {
+				t.Fatalf("Invalid layout: %v", err)
+			}
+
+			fake, err := LoadFakeProvider("fake", filepath.Join(dir, "fixtures.json"))
+			if err != nil {
+				t.Fatalf("LoadFakeProvider() error = %v", err)
+			}
+			RegisterProvider(fake)
+			defer delete(providers, fake.Name())
+
+			out := t.TempDir()
+			c := NewConverter(filepath.Join(dir, "input"), out, gc.Lang)
+			c.SetProvider(fake.Name())
+			c.SetSourceLanguage(gc.From)
+			c.SetLayout(layout)
+			c.SetScaffold(gc.Scaffold)
+			c.SetReportPath(filepath.Join(out, "conversion-report.json"))
+			c.SetLogOutput(io.Discard)
+			if err := c.Convert(); err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+
+			checkGolden(t, out, filepath.Join(dir, "expected"))
+		})
+	}
+}
+
+// checkGolden compares an output tree with its golden tree, or replaces the golden
+// tree with the output when -update is set
+func checkGolden(t *testing.T, got, want string) {
+	t.Helper()
+	if *update {
+		if err := os.RemoveAll(want); err != nil {
+			t.Fatalf("Failed to remove %s: %v", want, err)
+		}
+		if err := copyTree(got, want); err != nil {
+			t.Fatalf("Failed to update %s: %v", want, err)
+		}
+		return
+	}
+	compareTrees(t, got, want)
+}
+
+// compareTrees fails the test unless got and want contain the same files with the same contents
+func compareTrees(t *testing.T, got, want string) {
+	t.Helper()
+	read := func(root string) map[string]string {
+		files := map[string]string{}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			data, err := os.ReadFile(path)
+			files[filepath.ToSlash(rel)] = string(data)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", root, err)
+		}
+		return files
+	}
+
+	gotFiles, wantFiles := read(got), read(want)
+	for name, content := range wantFiles {
+		if gotContent, ok := gotFiles[name]; !ok {
+			t.Errorf("Missing output file %s", name)
+		} else if gotContent != content {
+			t.Errorf("Output %s differs from the golden file:\n%s", name, unifiedDiff(name, name, content, gotContent))
+		}
+	}
+	for name := range gotFiles {
+		if _, ok := wantFiles[name]; !ok {
+			t.Errorf("Unexpected output file %s", name)
+		}
+	}
+}
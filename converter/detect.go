@@ -0,0 +1,216 @@
+package converter
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Detection records how a file's source language was determined
+type Detection struct {
+	Language   string  `json:"language,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Method     string  `json:"method,omitempty"`
+}
+
+const (
+	// sniffLimit bounds how much of a file is read to detect its language
+	sniffLimit = 64 * 1024
+	// ambiguousConfidence is the confidence of a language guessed from an extension shared by several languages
+	ambiguousConfidence = 0.5
+)
+
+// SetSourceLanguage forces the source language of every text file, including
+// those whose language would not be detected; binary files are still copied
+func (c *Converter) SetSourceLanguage(lang string) {
+	c.sourceLang = lang
+}
+
+// detectFile determines the language of a file from its extension, shebang,
+// modeline or content, in decreasing order of reliability, unless it is overridden
+func (c *Converter) detectFile(path string) Detection {
+	if c.sourceLang != "" && isTextFile(path) {
+		return Detection{Language: c.sourceLang, Confidence: 1, Method: "override"}
+	}
+	return detectFileLanguage(path)
+}
+
+// isTextFile reports whether a file can be read and holds no NUL bytes
+func isTextFile(path string) bool {
+	head, err := readHead(path)
+	return err == nil && bytes.IndexByte(head, 0) < 0
+}
+
+func detectFileLanguage(path string) Detection {
+	// Extensions claimed by several languages, such as .h, are resolved from the content
+	var candidates []string
+	for _, lang := range languages.byExtension[strings.ToLower(filepath.Ext(path))] {
+		candidates = append(candidates, lang.Name)
+	}
+	ambiguous := len(candidates) > 1
+	if len(candidates) == 1 {
+		return Detection{Language: candidates[0], Confidence: 1, Method: "extension"}
+	}
+
+	head, err := readHead(path)
+	if err != nil || bytes.IndexByte(head, 0) >= 0 {
+		// Unreadable or binary files are copied untouched
+		return Detection{}
+	}
+	content := string(head)
+
+	if lang, ok := shebangLanguage(content); ok {
+		return Detection{Language: lang, Confidence: 0.95, Method: "shebang"}
+	}
+	if lang, ok := modelineLanguage(content); ok {
+		return Detection{Language: lang, Confidence: 0.9, Method: "modeline"}
+	}
+
+	if ambiguous {
+		// The extension already narrows the candidates, so any content evidence raises confidence above the fallback
+		if det := detectContent(content, candidates...); det.Language != "" {
+			det.Confidence = ambiguousConfidence + det.Confidence*(1-ambiguousConfidence)
+			det.Confidence = math.Round(det.Confidence*100) / 100
+			return det
+		}
+		// Fall back to the first candidate, which is what the extension usually means
+		return Detection{Language: candidates[0], Confidence: ambiguousConfidence, Method: "extension"}
+	}
+	return Detection{}
+}
+
+// readHead reads up to sniffLimit bytes from the start of a file
+func readHead(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, sniffLimit))
+}
+
+var (
+	vimModeline   = regexp.MustCompile(`(?m)\b(?:vi|vim|ex):.*?\b(?:ft|filetype|syntax)=([\w+#-]+)`)
+	emacsModeline = regexp.MustCompile(`(?m)-\*-\s*(?:.*?\bmode:\s*)?([\w+#-]+)\s*(?:;.*?)?-\*-`)
+)
+
+// modelineLanguage reads vim ("vim: set ft=python:") and emacs ("-*- mode: ruby -*-")
+// modelines from the first or last lines of a file
+func modelineLanguage(content string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	if len(lines) > 10 {
+		lines = append(lines[:5], lines[len(lines)-5:]...)
+	}
+	text := strings.Join(lines, "\n")
+
+	for _, re := range []*regexp.Regexp{vimModeline, emacsModeline} {
+		if m := re.FindStringSubmatch(text); m != nil {
+			if lang, ok := languages.lookup(m[1]); ok {
+				return lang.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// contentSignatures are patterns whose presence is characteristic of a language
+var contentSignatures = []struct {
+	lang    string
+	pattern *regexp.Regexp
+}{
+	{"Go", regexp.MustCompile(`(?m)^package\s+\w+\s*$`)},
+	{"Go", regexp.MustCompile(`(?m)^func\s+(\(\w+\s+\*?\w+\)\s*)?\w+\(`)},
+	{"Rust", regexp.MustCompile(`(?m)^\s*(pub\s+)?fn\s+\w+.*->|^\s*use\s+\w+::|\blet\s+mut\b`)},
+	{"Python", regexp.MustCompile(`(?m)^\s*def\s+\w+\(.*\)\s*(->\s*[\w\[\], ]+)?:\s*$`)},
+	{"Python", regexp.MustCompile(`(?m)^\s*(from\s+[\w.]+\s+)?import\s+[\w.]+(\s+as\s+\w+)?\s*$`)},
+	{"Java", regexp.MustCompile(`(?m)^\s*(public\s+)?(final\s+)?class\s+\w+.*\{|^package\s+[\w.]+;`)},
+	{"C#", regexp.MustCompile(`(?m)^\s*using\s+System[\w.]*;|^\s*namespace\s+[\w.]+`)},
+	{"C++", regexp.MustCompile(`#include\s*<(iostream|vector|string|memory|map)>|std::`)},
+	{"C", regexp.MustCompile(`#include\s*<(stdio|stdlib|string)\.h>`)},
+	{"TypeScript", regexp.MustCompile(`(?m)^\s*(export\s+)?(interface|type)\s+\w+|:\s*(string|number|boolean)\b`)},
+	{"JavaScript", regexp.MustCompile(`\b(const|let)\s+\w+\s*=|\bfunction\s+\w+\(|require\(['"]|module\.exports`)},
+	{"PHP", regexp.MustCompile(`<\?php`)},
+	{"Ruby", regexp.MustCompile(`(?m)^\s*(def\s+\w+[^:(]*$|end\s*$|require\s+['"])`)},
+	{"Kotlin", regexp.MustCompile(`(?m)^\s*fun\s+\w+\(|\bval\s+\w+\s*[:=]`)},
+	{"Swift", regexp.MustCompile(`(?m)^\s*import\s+(Foundation|UIKit|SwiftUI)\s*$|\bfunc\s+\w+\(.*\)\s*->`)},
+	{"Objective-C", regexp.MustCompile(`(?m)^\s*@(interface|implementation|end|property)\b|#import\s*[<"]`)},
+	{"Scala", regexp.MustCompile(`(?m)^\s*(case\s+class|object\s+\w+(\s+extends)?|def\s+\w+.*:\s*\w+\s*=)`)},
+	{"Elixir", regexp.MustCompile(`(?m)^\s*(defmodule\s+[\w.]+\s+do|defp?\s+\w+.*\bdo\s*$)`)},
+	{"Dart", regexp.MustCompile(`import\s+'package:|(?m)^\s*void\s+main\(\)\s*(async\s*)?\{`)},
+	{"Lua", regexp.MustCompile(`(?m)^\s*local\s+(function\s+)?\w+|\bthen\s*$|^\s*end\s*$`)},
+	{"Haskell", regexp.MustCompile(`(?m)^module\s+[\w.]+.*\bwhere\s*$|^\w+\s*::\s*[\w\[(]`)},
+	{"Perl", regexp.MustCompile(`(?m)^\s*use\s+(strict|warnings);|\bmy\s+[$@%]\w+`)},
+	{"R", regexp.MustCompile(`\w+\s*<-\s*function\s*\(|\blibrary\(\w+\)`)},
+	{"PowerShell", regexp.MustCompile(`(?mi)^\s*param\s*\(|\b(Write-Host|Get-\w+|Set-\w+)\b`)},
+}
+
+// detectLanguageFromContent guesses the language of source code that has no
+// usable file name, from its shebang, modeline or content signatures
+func detectLanguageFromContent(content string) Detection {
+	if lang, ok := shebangLanguage(content); ok {
+		return Detection{Language: lang, Confidence: 0.95, Method: "shebang"}
+	}
+	if lang, ok := modelineLanguage(content); ok {
+		return Detection{Language: lang, Confidence: 0.9, Method: "modeline"}
+	}
+	return detectContent(content)
+}
+
+// detectContent scores content signatures, optionally restricted to candidate
+// languages. Confidence reflects both how dominant the best language is and how
+// much evidence was found.
+func detectContent(content string, candidates ...string) Detection {
+	allowed := toSet(candidates...)
+	scores := map[string]int{}
+	best, bestScore, total := "", 0, 0
+	for _, sig := range contentSignatures {
+		if len(candidates) > 0 && !allowed[sig.lang] {
+			continue
+		}
+		n := len(sig.pattern.FindAllStringIndex(content, 50))
+		scores[sig.lang] += n
+		total += n
+		if scores[sig.lang] > bestScore {
+			best, bestScore = sig.lang, scores[sig.lang]
+		}
+	}
+	if bestScore == 0 {
+		return Detection{}
+	}
+
+	confidence := float64(bestScore) / float64(total) * math.Min(1, float64(bestScore)/3)
+	return Detection{Language: best, Confidence: math.Round(confidence*100) / 100, Method: "content"}
+}
+
+// shebangLanguage reads the interpreter from a "#!" line, including "/usr/bin/env NAME" forms
+func shebangLanguage(content string) (string, bool) {
+	if !strings.HasPrefix(content, "#!") {
+		return "", false
+	}
+
+	line := strings.SplitN(content, "\n", 2)[0]
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = field
+				break
+			}
+		}
+	}
+
+	// Strip version suffixes such as python3.12
+	if lang, ok := languages.byInterpreter[strings.TrimRight(interpreter, "0123456789.")]; ok {
+		return lang.Name, true
+	}
+	return "", false
+}
@@ -0,0 +1,139 @@
+package converter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDetectLanguageFromContent tests content sniffing for sources without a file name
+func TestDetectLanguageFromContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"shebang env", "#!/usr/bin/env python3\nprint('hi')\n", "Python"},
+		{"shebang path", "#!/usr/local/bin/node\nconsole.log(1)\n", "JavaScript"},
+		{"go", "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n}\n", "Go"},
+		{"python", "import os\n\ndef main():\n    print(os.getcwd())\n", "Python"},
+		{"java", "package demo;\n\npublic class App {\n}\n", "Java"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguageFromContent(tt.content); got.Language != tt.want {
+				t.Errorf("detectLanguageFromContent() = %q, want %q", got.Language, tt.want)
+			}
+		})
+	}
+}
+
+// TestConvertStream tests that stdin conversion writes only the converted code to the output writer
+func TestConvertStream(t *testing.T) {
+	mockResponse := "def main():\n    pass"
+	cleanup := setupMockGPT(mockResponse, nil)
+	defer cleanup()
+
+	var out, log bytes.Buffer
+	c := NewConverter("-", "", "python")
+	c.SetLogOutput(&log)
+	if err := c.ConvertStream(strings.NewReader("package main\n\nfunc main() {}\n"), &out, ""); err != nil {
+		t.Fatalf("ConvertStream() error = %v", err)
+	}
+
+	if out.String() != mockResponse {
+		t.Errorf("ConvertStream() output = %q, want %q", out.String(), mockResponse)
+	}
+	if !strings.Contains(log.String(), "Detected source language Go") {
+		t.Errorf("Expected detection to be logged, got %q", log.String())
+	}
+}
+
+// TestDetectFileLanguage tests detection from extensions, shebangs, modelines and content
+func TestDetectFileLanguage(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		content    string
+		wantLang   string
+		wantMethod string
+	}{
+		{"extension", "app.tsx", "export const App = () => null\n", "TypeScript", "extension"},
+		{"module extension", "index.mjs", "export default 1\n", "JavaScript", "extension"},
+		{"shebang script", "deploy", "#!/usr/bin/env python3\nimport sys\n", "Python", "shebang"},
+		{"vim modeline", "Rakefile", "task :default\n# vim: set ft=ruby:\n", "Ruby", "modeline"},
+		{"emacs modeline", "build", "// -*- mode: javascript -*-\nrun()\n", "JavaScript", "modeline"},
+		{"C header", "util.h", "#include <stdio.h>\nint add(int a, int b);\n", "C", "content"},
+		{"C++ header", "util.h", "#include <vector>\nstd::vector<int> values();\n", "C++", "content"},
+		{"Objective-C header", "View.h", "#import <Foundation/Foundation.h>\n@interface View : NSObject\n@end\n", "Objective-C", "content"},
+		{"bash script", "install", "#!/bin/bash\nset -e\n", "Shell", "shebang"},
+		{"plain text", "LICENSE", "Permission is hereby granted, free of charge\n", "", ""},
+		{"binary", "blob", "\x00\x01#!/bin/python\n", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			got := detectFileLanguage(path)
+			if got.Language != tt.wantLang || got.Method != tt.wantMethod {
+				t.Errorf("detectFileLanguage(%s) = %+v, want language %q by %q", tt.file, got, tt.wantLang, tt.wantMethod)
+			}
+		})
+	}
+}
+
+// TestDetectionRecordedInReport tests that detected languages and the -from override reach the report
+func TestDetectionRecordedInReport(t *testing.T) {
+	tempInput := t.TempDir()
+	writeSourceFiles(t, tempInput, map[string]string{
+		"main.go":   "package main\n",
+		"notes.txt": "Run the tool with -help.\n",
+		"logo.png":  "\x89PNG\r\n\x1a\n\x00\x00",
+	})
+
+	cleanup := setupMockGPT("# Converted code", nil)
+	defer cleanup()
+
+	c := NewConverter(tempInput, t.TempDir(), "python")
+	c.SetScaffold(false)
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	files := c.Report().Files
+	if len(files) != 3 || files[1].Detection == nil || files[1].Detection.Method != "extension" {
+		t.Fatalf("Report entry for main.go = %+v", files)
+	}
+	if files[0].Detection != nil || files[2].Source != "notes.txt" || files[2].Action != actionCopied || files[2].Detection != nil {
+		t.Errorf("Expected copied files without a detection, got %+v and %+v", files[0], files[2])
+	}
+
+	// The override also covers text files whose language is not detected, but not binary files
+	c = NewConverter(tempInput, t.TempDir(), "python")
+	c.SetScaffold(false)
+	c.SetLogOutput(io.Discard)
+	c.SetSourceLanguage("Go")
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	files = c.Report().Files
+	if len(files) != 3 {
+		t.Fatalf("Report() has %d files, want 3", len(files))
+	}
+	if files[0].Source != "logo.png" || files[0].Action != actionCopied || files[0].Detection != nil {
+		t.Errorf("Report entry for logo.png = %+v", files[0])
+	}
+	for _, f := range files[1:] {
+		if f.Action != actionConverted || f.Detection == nil || f.Detection.Method != "override" || f.Detection.Language != "Go" {
+			t.Errorf("Report entry for %s = %+v", f.Source, f)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// dialectRule marks a dialect when its pattern matches the source
+type dialectRule struct {
+	dialect string
+	pattern *regexp.Regexp
+}
+
+// dialectRules are checked in order and the first match wins, so newer or more
+// specific dialects come first
+var dialectRules = map[string][]dialectRule{
+	"Python": {
+		{"Python 2", regexp.MustCompile(`(?m)^\s*print\s+[^\s(=]|^\s*except\s+[\w.]+\s*,\s*\w+\s*:|\bxrange\(|\braw_input\(|\.iteritems\(\)|\bunicode\(|^#!.*python2`)},
+		{"Python 3", regexp.MustCompile(`(?m)\bf["']|^\s*async\s+def\b|\bdef\s+\w+\(.*\)\s*->|\bnonlocal\b|\bprint\(`)},
+	},
+	"Java": {
+		{"Java 17+", regexp.MustCompile(`\bsealed\s+(class|interface)\b|\bpermits\b`)},
+		{"Java 16+", regexp.MustCompile(`(?m)^\s*(public\s+)?record\s+\w+\s*\(|instanceof\s+\w+\s+\w+\s*[&)]`)},
+		{"Java 10+", regexp.MustCompile(`(?m)^\s*var\s+\w+\s*=`)},
+		{"Java 8+", regexp.MustCompile(`->|::\w+|\.stream\(\)`)},
+	},
+	"Go": {
+		{"Go 1.22+", regexp.MustCompile(`for\s+\w+\s*:=\s*range\s+\d+`)},
+		{"Go 1.18+", regexp.MustCompile(`\[\w+\s+(any|comparable|~?\w+(\s*\|\s*~?\w+)*)\]`)},
+	},
+	"C++": {
+		{"C++20", regexp.MustCompile(`\bconcept\s+\w+|\brequires\b|\bco_(await|yield|return)\b|<=>|std::span\b`)},
+		{"C++17", regexp.MustCompile(`if\s+constexpr|std::(optional|variant|string_view|filesystem)\b|auto\s*\[`)},
+		{"C++11", regexp.MustCompile(`\bnullptr\b|std::(unique_ptr|shared_ptr|move)\b|\bauto\s+\w+\s*=|\[[&=]?\]\s*\(`)},
+	},
+	"C#": {
+		{"C# 9+", regexp.MustCompile(`\brecord\s+\w+|\binit;|(?m)^\s*namespace\s+[\w.]+;`)},
+	},
+}
+
+// esmPattern and friends classify JavaScript and TypeScript module systems and syntax levels
+var (
+	esmPattern      = regexp.MustCompile(`(?m)^\s*(import\s+[^(]|export\s+)`)
+	commonJSPattern = regexp.MustCompile(`\brequire\(\s*['"]|module\.exports|\bexports\.\w+\s*=`)
+	es2015Pattern   = regexp.MustCompile(`\b(let|const|class)\s+\w|=>|` + "`")
+)
+
+// detectDialect identifies the language version or module system of source code,
+// returning an empty string when there is no distinguishing evidence
+func detectDialect(lang, content string) string {
+	switch lang {
+	case "JavaScript", "TypeScript":
+		var parts []string
+		if lang == "JavaScript" {
+			if es2015Pattern.MatchString(content) || esmPattern.MatchString(content) {
+				parts = append(parts, "ES2015+")
+			} else {
+				parts = append(parts, "ES5")
+			}
+		}
+		switch {
+		case esmPattern.MatchString(content):
+			parts = append(parts, "ESM")
+		case commonJSPattern.MatchString(content):
+			parts = append(parts, "CommonJS")
+		}
+		return strings.Join(parts, " ")
+	}
+
+	for _, rule := range dialectRules[lang] {
+		if rule.pattern.MatchString(content) {
+			return rule.dialect
+		}
+	}
+	return ""
+}
+
+// describeSource names the source language with its detected dialect, e.g. "Python 2" or "JavaScript (ES5)"
+func describeSource(lang, dialect string) string {
+	switch {
+	case dialect == "":
+		return lang
+	case strings.HasPrefix(dialect, lang):
+		return dialect
+	}
+	return lang + " (" + dialect + ")"
+}
@@ -0,0 +1,236 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is a single line of an edit script
+type diffOp struct {
+	kind byte // ' ' for equal lines, '-' for deletions and '+' for insertions
+	line string
+}
+
+// splitLines splits text into lines that keep their trailing newline
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script between two line slices using the
+// linear-space variant of Myers' algorithm: instead of keeping the search trace of
+// every edit distance, it finds the middle snake of an optimal path and recurses on
+// either side of it, so memory stays proportional to the input
+func diffLines(a, b []string) []diffOp {
+	d := differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// differ accumulates the edit script between a and b
+type differ struct {
+	a, b []string
+	ops  []diffOp
+}
+
+// compare appends the edit script that turns a[a0:a1] into b[b0:b1]
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.ops = append(d.ops, diffOp{' ', d.a[a0]})
+		a0, b0 = a0+1, b0+1
+	}
+	suffix := 0
+	for a0 < a1 && b0 < b1 && d.a[a1-1] == d.b[b1-1] {
+		a1, b1 = a1-1, b1-1
+		suffix++
+	}
+
+	switch {
+	case a0 == a1:
+		for _, line := range d.b[b0:b1] {
+			d.ops = append(d.ops, diffOp{'+', line})
+		}
+	case b0 == b1:
+		for _, line := range d.a[a0:a1] {
+			d.ops = append(d.ops, diffOp{'-', line})
+		}
+	default:
+		x0, y0, x1, y1 := d.middleSnake(a0, a1, b0, b1)
+		d.compare(a0, x0, b0, y0)
+		for _, line := range d.a[x0:x1] {
+			d.ops = append(d.ops, diffOp{' ', line})
+		}
+		d.compare(x1, a1, y1, b1)
+	}
+
+	for _, line := range d.a[a1 : a1+suffix] {
+		d.ops = append(d.ops, diffOp{' ', line})
+	}
+}
+
+// middleSnake searches forwards from the start and backwards from the end of
+// a[a0:a1] and b[b0:b1] at once, and returns the start and end of the snake where
+// the two searches meet. The inputs must differ in their first and last lines, so
+// the snake lies strictly between the corners and both halves are smaller.
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x0, y0, x1, y1 int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	offset := max + 1
+	// forward[k] is the furthest x reached on diagonal k = x - y from the start, and
+	// backward[k] the furthest distance from the end on diagonal k of the reversed inputs
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+
+	// inGrid reports whether the point x, y reached on diagonal k lies within the inputs
+	inGrid := func(x, k int) bool {
+		y := x - k
+		return x <= n && y >= 0 && y <= m
+	}
+
+	for e := 0; e <= max; e++ {
+		for k := -e; k <= e; k += 2 {
+			var x int
+			if k == -e || (k != e && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && y >= 0 && d.a[a0+x] == d.b[b0+y] {
+				x, y = x+1, y+1
+			}
+			forward[offset+k] = x
+
+			// With an odd delta the paths meet after a forward step
+			if r := delta - k; odd && r >= -(e-1) && r <= e-1 && inGrid(x, k) && inGrid(backward[offset+r], r) && x+backward[offset+r] >= n {
+				return a0 + startX, b0 + startY, a0 + x, b0 + y
+			}
+		}
+
+		for k := -e; k <= e; k += 2 {
+			var x int
+			if k == -e || (k != e && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && y >= 0 && d.a[a1-1-x] == d.b[b1-1-y] {
+				x, y = x+1, y+1
+			}
+			backward[offset+k] = x
+
+			// With an even delta the paths meet after a backward step
+			if r := delta - k; !odd && r >= -e && r <= e && inGrid(x, k) && inGrid(forward[offset+r], r) && x+forward[offset+r] >= n {
+				return a1 - x, b1 - y, a1 - startX, b1 - startY
+			}
+		}
+	}
+	// Two searches of at most max steps each always meet, so this is not reached;
+	// deleting the first half of a would still leave a valid, smaller problem
+	half := a0 + (n+1)/2
+	return half, b0, half, b0
+}
+
+// unifiedDiff renders the changes between two texts as a unified diff. Empty
+// names stand for /dev/null, so file creation and deletion can be expressed.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", diffName("a/", oldName), diffName("b/", newName))
+
+	// Positions in the old and new files before each op, 1-based
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	oldLine[0], newLine[0] = 1, 1
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.kind != '+' {
+			oldLine[i+1]++
+		}
+		if op.kind != '-' {
+			newLine[i+1]++
+		}
+	}
+
+	hasChanges := false
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		hasChanges = true
+
+		// Extend the hunk until a run of unchanged lines is long enough to split on
+		start := max(0, i-diffContext)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end = min(run, end+diffContext)
+				break
+			}
+			end = run
+		}
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLine[start], oldCount), hunkRange(newLine[start], newCount))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+
+	if !hasChanges {
+		return ""
+	}
+	return b.String()
+}
+
+func diffName(prefix, name string) string {
+	if name == "" {
+		return "/dev/null"
+	}
+	return prefix + name
+}
+
+// hunkRange formats a hunk header range; empty ranges point at the line before them
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
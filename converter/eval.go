@@ -0,0 +1,522 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// EvalConfig is one way of running conversions that an evaluation compares, such as a
+// provider or a prompt variant
+type EvalConfig struct {
+	Name     string   `json:"name"`
+	Provider string   `json:"provider"`
+	Guidance []string `json:"guidance,omitempty"`
+
+	// Params are the sampling settings the configuration converts with
+	Params ModelParams `json:"params,omitempty"`
+}
+
+// EvalCase is a run of a suite program together with the output it must produce
+type EvalCase struct {
+	VerifyCase
+	Stdout   string `json:"stdout"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// EvalProgram is a small source program in an evaluation suite. Each program lives in
+// its own directory holding a case.json, the sources under src/ and, optionally,
+// reference tests for target languages under tests/<language>/.
+type EvalProgram struct {
+	Name     string
+	Dir      string
+	Entry    string
+	Language string
+	Cases    []EvalCase
+
+	// tests maps a target language name to its reference test directory
+	tests map[string]string
+}
+
+// EvalTest is the outcome of one case or reference test suite run
+type EvalTest struct {
+	Name    string     `json:"name"`
+	Passed  bool       `json:"passed"`
+	Skipped bool       `json:"skipped,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	Result  *RunResult `json:"result,omitempty"`
+}
+
+// EvalResult records how one configuration converted and ran one program for one target
+type EvalResult struct {
+	Config       string     `json:"config"`
+	Program      string     `json:"program"`
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Converted    bool       `json:"converted"`
+	Parsed       bool       `json:"parsed"`
+	ParseSkipped bool       `json:"parse_skipped,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	Tests        []EvalTest `json:"tests,omitempty"`
+	Usage        Usage      `json:"usage"`
+	Cost         float64    `json:"cost_usd"`
+	Unpriced     []string   `json:"unpriced_models,omitempty"`
+}
+
+// EvalSummary aggregates the results of one configuration and language pair
+type EvalSummary struct {
+	Config       string   `json:"config"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	Programs     int      `json:"programs"`
+	Converted    int      `json:"converted"`
+	Parsed       int      `json:"parsed"`
+	ParseChecked int      `json:"parse_checked"`
+	TestsPassed  int      `json:"tests_passed"`
+	TestsTotal   int      `json:"tests_total"`
+	PassRate     float64  `json:"pass_rate"`
+	Usage        Usage    `json:"usage"`
+	Cost         float64  `json:"cost_usd"`
+	Unpriced     []string `json:"unpriced_models,omitempty"`
+}
+
+// EvalReport holds the summaries and individual results of an evaluation
+type EvalReport struct {
+	Summaries []EvalSummary `json:"summaries"`
+	Results   []EvalResult  `json:"results"`
+}
+
+// Evaluator converts a suite of programs with several configurations and runs the results
+type Evaluator struct {
+	suite   string
+	targets []string
+	configs []EvalConfig
+	prices  map[string]Price
+	timeout time.Duration
+	workDir string
+	log     io.Writer
+
+	// policy and blockOnSecrets restrict what every configuration may send, as for Converter
+	policy         *EgressPolicy
+	blockOnSecrets bool
+}
+
+// NewEvaluator creates an Evaluator for the programs under suite and the given target
+// languages, which may carry versions and modifiers like the -lang flag
+func NewEvaluator(suite string, targets []string) *Evaluator {
+	return &Evaluator{
+		suite:   suite,
+		targets: targets,
+		configs: []EvalConfig{{Name: defaultProviderName, Provider: defaultProviderName}},
+		prices:  defaultPrices,
+		timeout: defaultVerifyTimeout,
+		log:     os.Stdout,
+	}
+}
+
+// SetConfigs replaces the default configuration, which uses the default provider
+func (e *Evaluator) SetConfigs(configs []EvalConfig) {
+	if len(configs) > 0 {
+		e.configs = configs
+	}
+}
+
+// SetPrices adds or overrides model prices used to estimate cost
+func (e *Evaluator) SetPrices(prices map[string]Price) {
+	e.prices = mergePrices(e.prices, prices)
+}
+
+// SetTimeout bounds each program and test suite run
+func (e *Evaluator) SetTimeout(timeout time.Duration) {
+	e.timeout = timeout
+}
+
+// SetWorkDir keeps the converted programs under dir instead of a temporary directory
+func (e *Evaluator) SetWorkDir(dir string) {
+	e.workDir = dir
+}
+
+// SetEgressPolicy restricts which providers each suite file may be sent to, in every configuration
+func (e *Evaluator) SetEgressPolicy(policy *EgressPolicy) {
+	e.policy = policy
+}
+
+// SetBlockOnSecrets refuses to send suite files that contain secrets to any configuration's model
+func (e *Evaluator) SetBlockOnSecrets(enabled bool) {
+	e.blockOnSecrets = enabled
+}
+
+// SetLogOutput redirects progress messages, which go to stdout by default
+func (e *Evaluator) SetLogOutput(w io.Writer) {
+	e.log = w
+}
+
+// LoadEvalConfigs reads configurations and prices from a JSON file of the form
+// {"providers": {...}, "configurations": [...], "pricing": {...}}. Declared
+// providers are registered like those of an egress policy.
+func LoadEvalConfigs(path string) ([]EvalConfig, map[string]Price, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read eval config %s: %w", path, err)
+	}
+	var file struct {
+		Providers      map[string]ProviderConfig `json:"providers"`
+		Configurations []EvalConfig              `json:"configurations"`
+		Pricing        map[string]Price          `json:"pricing"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse eval config %s: %w", path, err)
+	}
+
+	for name, config := range file.Providers {
+		p, err := NewCompatibleProvider(name, config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("eval config %s: %w", path, err)
+		}
+		RegisterProvider(p)
+	}
+	seen := map[string]bool{}
+	for i, config := range file.Configurations {
+		if config.Name == "" {
+			return nil, nil, fmt.Errorf("eval config %s: configuration %d has no name", path, i+1)
+		}
+		if seen[config.Name] {
+			return nil, nil, fmt.Errorf("eval config %s: duplicate configuration %q", path, config.Name)
+		}
+		seen[config.Name] = true
+		if config.Provider == "" {
+			file.Configurations[i].Provider = defaultProviderName
+		}
+	}
+	return file.Configurations, file.Pricing, nil
+}
+
+// LoadEvalSuite reads every program directory under suite, in alphabetical order
+func LoadEvalSuite(suite string) ([]EvalProgram, error) {
+	entries, err := os.ReadDir(suite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite %s: %w", suite, err)
+	}
+
+	var programs []EvalProgram
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		program, err := loadEvalProgram(filepath.Join(suite, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	if len(programs) == 0 {
+		return nil, fmt.Errorf("no programs found in suite %s", suite)
+	}
+	return programs, nil
+}
+
+// loadEvalProgram reads a program's case.json and finds its reference tests
+func loadEvalProgram(dir string) (EvalProgram, error) {
+	casePath := filepath.Join(dir, "case.json")
+	data, err := os.ReadFile(casePath)
+	if err != nil {
+		return EvalProgram{}, fmt.Errorf("failed to read %s: %w", casePath, err)
+	}
+	var spec struct {
+		Entry string     `json:"entry"`
+		Cases []EvalCase `json:"cases"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return EvalProgram{}, fmt.Errorf("failed to parse %s: %w", casePath, err)
+	}
+	if spec.Entry == "" {
+		return EvalProgram{}, fmt.Errorf("%s: entry is required", casePath)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", filepath.FromSlash(spec.Entry))); err != nil {
+		return EvalProgram{}, fmt.Errorf("%s: entry %s not found under src", casePath, spec.Entry)
+	}
+	lang, ok := detectLanguage(spec.Entry)
+	if !ok {
+		return EvalProgram{}, fmt.Errorf("%s: cannot tell the language of %s", casePath, spec.Entry)
+	}
+	for i := range spec.Cases {
+		if spec.Cases[i].Name == "" {
+			spec.Cases[i].Name = fmt.Sprintf("case %d", i+1)
+		}
+	}
+
+	program := EvalProgram{
+		Name:     filepath.Base(dir),
+		Dir:      dir,
+		Entry:    filepath.ToSlash(spec.Entry),
+		Language: lang,
+		Cases:    spec.Cases,
+		tests:    map[string]string{},
+	}
+	testDirs, err := os.ReadDir(filepath.Join(dir, "tests"))
+	if err != nil && !os.IsNotExist(err) {
+		return EvalProgram{}, fmt.Errorf("failed to read tests of %s: %w", program.Name, err)
+	}
+	for _, d := range testDirs {
+		if !d.IsDir() {
+			continue
+		}
+		testLang, err := ResolveLanguage(d.Name())
+		if err != nil {
+			return EvalProgram{}, fmt.Errorf("tests of %s: %w", program.Name, err)
+		}
+		program.tests[testLang.Name] = filepath.Join(dir, "tests", d.Name())
+	}
+	return program, nil
+}
+
+// Run converts every program with every configuration for every target language,
+// then runs the cases and reference tests against the converted programs
+func (e *Evaluator) Run() (EvalReport, error) {
+	programs, err := LoadEvalSuite(e.suite)
+	if err != nil {
+		return EvalReport{}, err
+	}
+	for _, target := range e.targets {
+		if _, err := ResolveLanguage(ParseTargetSpec(target).Name); err != nil {
+			return EvalReport{}, err
+		}
+	}
+	for _, config := range e.configs {
+		if _, err := lookupProvider(config.Provider); err != nil {
+			return EvalReport{}, fmt.Errorf("configuration %s: %w", config.Name, err)
+		}
+	}
+
+	workDir := e.workDir
+	if workDir == "" {
+		tmp, err := os.MkdirTemp("", "code-converter-eval")
+		if err != nil {
+			return EvalReport{}, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		workDir = tmp
+	}
+
+	var report EvalReport
+	for _, config := range e.configs {
+		for _, program := range programs {
+			for _, target := range e.targets {
+				outputDir := filepath.Join(workDir, pathSafe(config.Name), program.Name, pathSafe(target))
+				report.Results = append(report.Results, e.evaluate(config, program, target, outputDir))
+			}
+		}
+	}
+	report.Summaries = summarizeEval(report.Results)
+	return report, nil
+}
+
+// pathSafe turns a configuration name or target spec into a directory name
+func pathSafe(name string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", " ", "-", "@", "-", ":", "-").Replace(name)
+}
+
+// evaluate converts one program for one target and runs its cases and reference tests
+func (e *Evaluator) evaluate(config EvalConfig, program EvalProgram, target, outputDir string) EvalResult {
+	spec := ParseTargetSpec(target)
+	lang, _ := languages.lookup(spec.Name)
+	result := EvalResult{Config: config.Name, Program: program.Name, From: program.Language, To: spec.Describe()}
+	fmt.Fprintf(e.log, "Evaluating %s: %s to %s with %s\n", program.Name, result.From, result.To, config.Name)
+
+	c := NewConverter(filepath.Join(program.Dir, "src"), outputDir, target)
+	c.SetScaffold(false)
+	c.SetValidate(true)
+	c.SetProvider(config.Provider)
+	c.SetEgressPolicy(e.policy)
+	c.SetBlockOnSecrets(e.blockOnSecrets)
+	c.SetModelParams(config.Params)
+	c.SetLogOutput(e.log)
+	for _, g := range config.Guidance {
+		c.AddGuidance(g)
+	}
+	convertErr := c.Convert()
+	if convertErr != nil {
+		result.Error = convertErr.Error()
+	}
+
+	conversion := c.Report()
+	result.Usage, result.Cost, result.Unpriced = estimateCost(e.prices, conversion.Usage)
+
+	var entry *FileReport
+	blocked := false
+	for i, f := range conversion.Files {
+		if f.Source == program.Entry && f.Action == actionConverted {
+			entry = &conversion.Files[i]
+		}
+		blocked = blocked || (f.Source == program.Entry && f.Action == actionBlocked)
+	}
+	if entry != nil && convertErr == nil {
+		result.Converted = true
+		if entry.Validation == nil || entry.Validation.Skipped {
+			result.ParseSkipped = true
+		} else {
+			result.Parsed = entry.Validation.Passed
+		}
+	} else if blocked {
+		result.Error = fmt.Sprintf("%s was %v", program.Entry, ErrBlocked)
+	} else if result.Error == "" {
+		result.Error = fmt.Sprintf("%s was not converted", program.Entry)
+	}
+
+	for _, vc := range program.Cases {
+		test := EvalTest{Name: vc.Name}
+		if result.Converted {
+			e.runCase(&test, lang, filepath.Join(outputDir, filepath.FromSlash(entry.Output)), vc)
+		} else {
+			test.Reason = "conversion failed"
+		}
+		result.Tests = append(result.Tests, test)
+	}
+	if dir, ok := program.tests[lang.Name]; ok {
+		test := EvalTest{Name: "reference tests"}
+		if result.Converted {
+			e.runTests(&test, lang, dir, outputDir)
+		} else {
+			test.Reason = "conversion failed"
+		}
+		result.Tests = append(result.Tests, test)
+	}
+	return result
+}
+
+// runCase runs the converted entry point and compares its output and exit code with the case
+func (e *Evaluator) runCase(test *EvalTest, lang *Language, path string, vc EvalCase) {
+	if len(lang.Run) == 0 {
+		test.Skipped, test.Reason = true, "no run command configured for "+lang.Name
+		return
+	}
+	run, err := executeCommand(lang.Run, path, filepath.Dir(path), vc.VerifyCase, e.timeout, e.log)
+	if err != nil {
+		test.Skipped, test.Reason = true, err.Error()
+		return
+	}
+	test.Result = &run
+
+	var mismatches []string
+	if run.ExitCode != vc.ExitCode {
+		mismatches = append(mismatches, fmt.Sprintf("exit code %d, want %d", run.ExitCode, vc.ExitCode))
+	}
+	if trimOutput(run.Stdout) != trimOutput(vc.Stdout) {
+		mismatches = append(mismatches, "stdout differs")
+	}
+	test.Passed = len(mismatches) == 0
+	test.Reason = strings.Join(mismatches, "; ")
+}
+
+// runTests copies the reference tests next to the converted program and runs the
+// target language's test command
+func (e *Evaluator) runTests(test *EvalTest, lang *Language, testDir, outputDir string) {
+	if len(lang.TestCommand) == 0 {
+		test.Skipped, test.Reason = true, "no test command configured for "+lang.Name
+		return
+	}
+	if err := copyTree(testDir, outputDir); err != nil {
+		test.Reason = fmt.Sprintf("failed to copy reference tests: %v", err)
+		return
+	}
+	abs, err := filepath.Abs(outputDir)
+	if err != nil {
+		test.Reason = fmt.Sprintf("failed to resolve %s: %v", outputDir, err)
+		return
+	}
+	run, err := executeCommand(lang.TestCommand, abs, abs, VerifyCase{}, e.timeout, e.log)
+	if err != nil {
+		test.Skipped, test.Reason = true, err.Error()
+		return
+	}
+	test.Result = &run
+	test.Passed = run.ExitCode == 0
+	if !test.Passed {
+		test.Reason = fmt.Sprintf("exit code %d", run.ExitCode)
+	}
+}
+
+// summarizeEval aggregates results by configuration and language pair, keeping the
+// order in which each combination first appears
+func summarizeEval(results []EvalResult) []EvalSummary {
+	var summaries []EvalSummary
+	index := map[[3]string]int{}
+	for _, r := range results {
+		key := [3]string{r.Config, r.From, r.To}
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, EvalSummary{Config: r.Config, From: r.From, To: r.To})
+		}
+		s := &summaries[i]
+		s.Programs++
+		if r.Converted {
+			s.Converted++
+		}
+		if r.Converted && !r.ParseSkipped {
+			s.ParseChecked++
+			if r.Parsed {
+				s.Parsed++
+			}
+		}
+		for _, t := range r.Tests {
+			if t.Skipped {
+				continue
+			}
+			s.TestsTotal++
+			if t.Passed {
+				s.TestsPassed++
+			}
+		}
+		s.Usage = addUsage(s.Usage, r.Usage)
+		s.Cost += r.Cost
+		for _, model := range r.Unpriced {
+			if !containsString(s.Unpriced, model) {
+				s.Unpriced = append(s.Unpriced, model)
+			}
+		}
+	}
+	for i := range summaries {
+		if s := &summaries[i]; s.TestsTotal > 0 {
+			s.PassRate = float64(s.TestsPassed) / float64(s.TestsTotal)
+		}
+	}
+	return summaries
+}
+
+// WriteTable prints the summaries as an aligned table. Parse counts only include
+// programs whose validator could run, and costs of models without a price are shown as "?".
+func (r EvalReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tFROM\tTO\tCONVERTED\tPARSED\tTESTS\tPASS RATE\tTOKENS\tCOST")
+	for _, s := range r.Summaries {
+		parsed, passRate := "-", "-"
+		if s.ParseChecked > 0 {
+			parsed = fmt.Sprintf("%d/%d", s.Parsed, s.ParseChecked)
+		}
+		if s.TestsTotal > 0 {
+			passRate = fmt.Sprintf("%.0f%%", 100*s.PassRate)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%s\t%d/%d\t%s\t%d\t%s\n",
+			s.Config, s.From, s.To, s.Converted, s.Programs, parsed, s.TestsPassed, s.TestsTotal, passRate, s.Usage.TotalTokens, formatCost(s.Cost, s.Unpriced))
+	}
+	return tw.Flush()
+}
+
+// WriteEvalReport saves an evaluation report as JSON
+func WriteEvalReport(report EvalReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode eval report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write eval report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package converter
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pricedProvider answers every prompt with the same text and reports a model and usage
+type pricedProvider struct {
+	name, text, model string
+}
+
+func (p pricedProvider) Name() string { return p.name }
+
+func (p pricedProvider) Generate(prompt string) (string, error) {
+	return p.text, nil
+}
+
+func (p pricedProvider) Complete(prompt string) (Completion, error) {
+	return Completion{Text: p.text, Model: p.model, Usage: Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}}, nil
+}
+
+// TestEvaluator tests converting a suite with two configurations and scoring the runs
+func TestEvaluator(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	suite := t.TempDir()
+	writeSourceFiles(t, suite, map[string]string{
+		"greet/case.json": `{"entry": "main.py", "cases": [
+			{"name": "world", "stdout": "Hello, world!\n"},
+			{"name": "name", "args": ["Ada"], "stdout": "Hello, Ada!\n"},
+			{"name": "too many", "args": ["a", "b"], "stdout": "", "exit_code": 2}
+		]}`,
+		"greet/src/main.py":            "import sys\nprint('Hello, ' + (sys.argv[1] if len(sys.argv) > 1 else 'world') + '!')\n",
+		"greet/tests/bash/test/x.bats": "@test 'x' { true; }\n",
+	})
+
+	good := "#!/bin/bash\nset -euo pipefail\nif [ $# -gt 1 ]; then exit 2; fi\necho \"Hello, ${1:-world}!\"\n"
+	RegisterProvider(pricedProvider{"test-good", good, "gpt-4o-mini-2024-07-18"})
+	RegisterProvider(pricedProvider{"test-broken", "echo \"Hello, ${1:-world}!\nif then\n", "local-model"})
+	defer delete(providers, "test-good")
+	defer delete(providers, "test-broken")
+
+	e := NewEvaluator(suite, []string{"bash"})
+	e.SetConfigs([]EvalConfig{{Name: "good", Provider: "test-good"}, {Name: "broken", Provider: "test-broken"}})
+	e.SetLogOutput(io.Discard)
+	report, err := e.Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Results) != 2 || len(report.Summaries) != 2 {
+		t.Fatalf("Expected 2 results and summaries, got %+v", report)
+	}
+
+	passing := report.Summaries[0]
+	if passing.Config != "good" || passing.From != "Python" || passing.Converted != 1 || passing.Parsed != 1 || passing.ParseChecked != 1 {
+		t.Errorf("Unexpected summary for the good configuration: %+v", passing)
+	}
+	// bats is not needed: the reference tests are skipped when it is missing
+	if passing.TestsTotal < 3 || passing.TestsPassed != passing.TestsTotal {
+		t.Errorf("Expected every case to pass, got %d/%d: %+v", passing.TestsPassed, passing.TestsTotal, report.Results[0].Tests)
+	}
+	if passing.Usage.TotalTokens != 1500 || math.Abs(passing.Cost-0.00045) > 1e-9 || len(passing.Unpriced) != 0 {
+		t.Errorf("Unexpected usage or cost: %+v %f %v", passing.Usage, passing.Cost, passing.Unpriced)
+	}
+
+	broken := report.Summaries[1]
+	if broken.Parsed != 0 || broken.ParseChecked != 1 || broken.TestsPassed == broken.TestsTotal || len(broken.Unpriced) != 1 {
+		t.Errorf("Unexpected summary for the broken configuration: %+v", broken)
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "100%") || !strings.Contains(lines[1], "$0.0004") || !strings.HasSuffix(lines[2], "?") {
+		t.Errorf("Unexpected table:\n%s", table.String())
+	}
+
+	if _, err := NewEvaluator(filepath.Join(suite, "missing"), []string{"bash"}).Run(); err == nil {
+		t.Errorf("Expected an error for a missing suite")
+	}
+}
+
+// TestEvaluatorEgress tests that suite files the egress policy denies are never sent
+func TestEvaluatorEgress(t *testing.T) {
+	suite := t.TempDir()
+	writeSourceFiles(t, suite, map[string]string{
+		"greet/case.json":   `{"entry": "main.py", "cases": [{"name": "world", "stdout": "Hello, world!\n"}]}`,
+		"greet/src/main.py": "print('Hello, world!')\n",
+	})
+	fake, err := NewFakeProvider("test-denied", FakeResponse{Response: "echo 'Hello, world!'\n"})
+	if err != nil {
+		t.Fatalf("NewFakeProvider() error = %v", err)
+	}
+	RegisterProvider(fake)
+	defer delete(providers, "test-denied")
+
+	e := NewEvaluator(suite, []string{"bash"})
+	e.SetConfigs([]EvalConfig{{Name: "denied", Provider: "test-denied"}})
+	e.SetEgressPolicy(&EgressPolicy{Rules: []EgressRule{{Paths: []string{"**/*.py"}, Providers: []string{"openai"}}}})
+	e.SetWorkDir(t.TempDir())
+	e.SetLogOutput(io.Discard)
+	report, err := e.Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if requests := fake.Requests(); len(requests) != 0 {
+		t.Errorf("Expected nothing to be sent, got %+v", requests)
+	}
+	if len(report.Results) != 1 || report.Results[0].Converted || !strings.Contains(report.Results[0].Error, "blocked") {
+		t.Errorf("Expected the entry point to be reported as blocked, got %+v", report.Results)
+	}
+}
@@ -0,0 +1,227 @@
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// ErrRateLimited is returned by providers when the backend refuses a request because of its rate limit
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// fileBinder is implemented by providers that need to know which file a prompt is about
+type fileBinder interface {
+	ForFile(relPath, sourceLang, targetLang string) Provider
+}
+
+// FakeResponse maps matching prompts to a canned response. A response matches
+// when every selector it sets matches: Path is a glob on the slash-separated
+// relative path of the file being sent, From and To name the source and target
+// languages, and Pattern is a regular expression searched for in the prompt. A
+// response without selectors matches every prompt.
+type FakeResponse struct {
+	Path    string `json:"path,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+
+	// Response is the text returned, or File names a fixture file holding it,
+	// relative to the fixture file's directory
+	Response string `json:"response,omitempty"`
+	File     string `json:"file,omitempty"`
+
+	// Error fails matching requests, and RateLimit fails the first n of them with
+	// ErrRateLimited before the response is served
+	Error     string `json:"error,omitempty"`
+	RateLimit int    `json:"rate_limit,omitempty"`
+
+	// Latency delays the response, e.g. "250ms", and Truncate cuts it to this
+	// many bytes, as when a model runs out of output tokens
+	Latency  string `json:"latency,omitempty"`
+	Truncate int    `json:"truncate,omitempty"`
+
+	pattern *regexp.Regexp
+	latency time.Duration
+}
+
+// FakeRequest is a prompt received by a FakeProvider
+type FakeRequest struct {
+	File   string
+	From   string
+	To     string
+	Prompt string
+}
+
+// FakeProvider answers prompts from fixtures instead of a model, for tests and demos
+type FakeProvider struct {
+	name      string
+	responses []FakeResponse
+
+	mu       sync.Mutex
+	hits     map[int]int
+	requests []FakeRequest
+}
+
+// NewFakeProvider creates a provider that answers with the first matching response
+func NewFakeProvider(name string, responses ...FakeResponse) (*FakeProvider, error) {
+	p := &FakeProvider{name: name, hits: map[int]int{}}
+	for i, r := range responses {
+		if r.Path != "" {
+			if _, err := doublestar.Match(r.Path, ""); err != nil {
+				return nil, fmt.Errorf("response %d: invalid path pattern %q: %w", i+1, r.Path, err)
+			}
+		}
+		for _, lang := range []*string{&r.From, &r.To} {
+			if *lang == "" {
+				continue
+			}
+			resolved, err := ResolveLanguage(*lang)
+			if err != nil {
+				return nil, fmt.Errorf("response %d: %w", i+1, err)
+			}
+			*lang = resolved.Name
+		}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("response %d: invalid pattern %q: %w", i+1, r.Pattern, err)
+			}
+			r.pattern = re
+		}
+		if r.Latency != "" {
+			d, err := time.ParseDuration(r.Latency)
+			if err != nil {
+				return nil, fmt.Errorf("response %d: invalid latency %q: %w", i+1, r.Latency, err)
+			}
+			r.latency = d
+		}
+		p.responses = append(p.responses, r)
+	}
+	return p, nil
+}
+
+// LoadFakeProvider reads fixtures from a JSON file of the form {"responses": [...]}.
+// Response files are read relative to the fixture file.
+func LoadFakeProvider(name, path string) (*FakeProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures %s: %w", path, err)
+	}
+	var fixtures struct {
+		Responses []FakeResponse `json:"responses"`
+	}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+	}
+
+	for i, r := range fixtures.Responses {
+		if r.File == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(path), filepath.FromSlash(r.File)))
+		if err != nil {
+			return nil, fmt.Errorf("fixtures %s: response %d: %w", path, i+1, err)
+		}
+		fixtures.Responses[i].Response = string(content)
+	}
+
+	p, err := NewFakeProvider(name, fixtures.Responses...)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func (p *FakeProvider) Name() string { return p.name }
+
+func (p *FakeProvider) Generate(prompt string) (string, error) {
+	return p.respond(FakeRequest{Prompt: prompt})
+}
+
+// ForFile returns a view of the provider that matches responses against the given file and languages
+func (p *FakeProvider) ForFile(relPath, sourceLang, targetLang string) Provider {
+	return boundFakeProvider{p, FakeRequest{File: filepath.ToSlash(relPath), From: sourceLang, To: targetLang}}
+}
+
+// Requests returns every prompt the provider has received, in order
+func (p *FakeProvider) Requests() []FakeRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]FakeRequest(nil), p.requests...)
+}
+
+// respond serves the first response matching the request
+func (p *FakeProvider) respond(req FakeRequest) (string, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	index := -1
+	for i, r := range p.responses {
+		if r.matches(req) {
+			index = i
+			break
+		}
+	}
+	hits := 0
+	if index >= 0 {
+		hits = p.hits[index]
+		p.hits[index]++
+	}
+	p.mu.Unlock()
+
+	if index < 0 {
+		about := req.File
+		if about == "" {
+			about = fmt.Sprintf("%.60q", req.Prompt)
+		}
+		return "", fmt.Errorf("%s: no fake response matches the prompt for %s", p.name, about)
+	}
+
+	r := p.responses[index]
+	time.Sleep(r.latency)
+	if hits < r.RateLimit {
+		return "", fmt.Errorf("%s: %w", p.name, ErrRateLimited)
+	}
+	if r.Error != "" {
+		return "", fmt.Errorf("%s: %s", p.name, r.Error)
+	}
+	if r.Truncate > 0 && r.Truncate < len(r.Response) {
+		return r.Response[:r.Truncate], nil
+	}
+	return r.Response, nil
+}
+
+// matches reports whether every selector the response sets matches the request
+func (r FakeResponse) matches(req FakeRequest) bool {
+	if r.Path != "" {
+		if ok, _ := doublestar.Match(r.Path, req.File); !ok {
+			return false
+		}
+	}
+	if r.From != "" && !strings.EqualFold(r.From, req.From) {
+		return false
+	}
+	if r.To != "" && !strings.EqualFold(r.To, req.To) {
+		return false
+	}
+	return r.pattern == nil || r.pattern.MatchString(req.Prompt)
+}
+
+// boundFakeProvider is a FakeProvider answering prompts about one file
+type boundFakeProvider struct {
+	*FakeProvider
+	request FakeRequest
+}
+
+func (p boundFakeProvider) Generate(prompt string) (string, error) {
+	req := p.request
+	req.Prompt = prompt
+	return p.respond(req)
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFakeProvider tests matching fixtures by path, language pair and pattern, and the simulated failures
+func TestFakeProvider(t *testing.T) {
+	fake, err := NewFakeProvider("fake",
+		FakeResponse{Path: "internal/**", Error: "model overloaded"},
+		FakeResponse{Path: "**/*.go", From: "golang", To: "python", Response: "go to python"},
+		FakeResponse{Path: "flaky.py", RateLimit: 2, Response: "finally"},
+		FakeResponse{Path: "slow.py", Latency: "20ms", Response: "slow"},
+		FakeResponse{Pattern: `class \w+`, Response: "a class, cut short", Truncate: 7},
+	)
+	if err != nil {
+		t.Fatalf("NewFakeProvider() error = %v", err)
+	}
+
+	ask := func(file, from, to, prompt string) (string, error) {
+		return fake.ForFile(file, from, to).Generate(prompt)
+	}
+
+	if got, err := ask("cmd/main.go", "Go", "Python", "x"); err != nil || got != "go to python" {
+		t.Errorf("Path and language match = %q, %v", got, err)
+	}
+	if _, err := ask("cmd/main.go", "Go", "Rust", "x"); err == nil || !strings.Contains(err.Error(), "no fake response matches") {
+		t.Errorf("Expected no match for another target, got %v", err)
+	}
+	if _, err := ask("internal/db.go", "Go", "Python", "x"); err == nil || !strings.Contains(err.Error(), "model overloaded") {
+		t.Errorf("Expected the simulated error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := ask("flaky.py", "Python", "Go", "x"); !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Request %d: expected ErrRateLimited, got %v", i+1, err)
+		}
+	}
+	if got, err := ask("flaky.py", "Python", "Go", "x"); err != nil || got != "finally" {
+		t.Errorf("Expected the response after the rate limit, got %q, %v", got, err)
+	}
+
+	start := time.Now()
+	if _, err := ask("slow.py", "Python", "Go", "x"); err != nil || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Expected a delayed response, got %v after %v", err, time.Since(start))
+	}
+
+	// Unbound requests only match on the prompt
+	if got, err := fake.Generate("class Foo:"); err != nil || got != "a class" {
+		t.Errorf("Expected a truncated response, got %q, %v", got, err)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 8 || requests[0].File != "cmd/main.go" || requests[0].From != "Go" || requests[7].Prompt != "class Foo:" {
+		t.Errorf("Unexpected recorded requests: %+v", requests)
+	}
+
+	if _, err := NewFakeProvider("fake", FakeResponse{Pattern: "("}); err == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,203 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Failure classes that move a file on to the next provider of the fallback chain
+const (
+	FallbackError     = "error"
+	FallbackRateLimit = "rate_limit"
+	FallbackContext   = "context"
+	FallbackRefusal   = "refusal"
+	FallbackInvalid   = "invalid"
+)
+
+// fallbackClasses lists every failure class, which is also the default set of conditions
+var fallbackClasses = []string{FallbackError, FallbackRateLimit, FallbackContext, FallbackRefusal, FallbackInvalid}
+
+// refusalPattern matches responses in which the model declines instead of returning code
+var refusalPattern = regexp.MustCompile(`(?i)^\s*(?:I'm sorry|I am sorry|Sorry, I|I can(?:not|'t)|I'm (?:not able|unable)|I am (?:not able|unable)|I won't|As an AI)`)
+
+// contextPattern matches error messages about prompts that exceed the model's context window
+var contextPattern = regexp.MustCompile(`(?i)context[_ ]length|context window|maximum context|too many tokens|prompt is too long`)
+
+// FallbackAttempt records a provider of the fallback chain that did not produce the file
+type FallbackAttempt struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	Failure  string `json:"failure"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ParseFallbackConditions validates a comma-separated list of failure classes given on the command line
+func ParseFallbackConditions(list string) ([]string, error) {
+	var classes []string
+	for _, class := range strings.Split(list, ",") {
+		class = strings.TrimSpace(class)
+		if class == "" {
+			continue
+		}
+		if !containsString(fallbackClasses, class) {
+			return nil, fmt.Errorf("unknown fallback condition %q (available: %s)", class, strings.Join(fallbackClasses, ", "))
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// SetFallback sets the providers tried in order when the selected one fails on a file
+func (c *Converter) SetFallback(providers []string) {
+	c.fallback = providers
+}
+
+// SetFallbackConditions limits the failures that move a file on to the next provider;
+// every failure class is a condition by default
+func (c *Converter) SetFallbackConditions(classes []string) {
+	c.fallbackOn = classes
+}
+
+// modelRecorder remembers the model of the last successful response
+type modelRecorder struct {
+	Provider
+	model *string
+}
+
+func (p modelRecorder) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p modelRecorder) Complete(prompt string) (Completion, error) {
+	completion, err := complete(p.Provider, prompt)
+	if err == nil && completion.Model != "" {
+		*p.model = completion.Model
+	}
+	return completion, err
+}
+
+func (p modelRecorder) WithParams(params ModelParams) Provider {
+	return modelRecorder{Provider: withParams(p.Provider, params), model: p.model}
+}
+
+// convertWithFallback converts source with provider and, when it fails in one of the
+// configured ways, with each fallback provider in turn. The provider and model that
+// produced the file, and the attempts that did not, are recorded in entry. When every
+// provider produces invalid code, the first invalid conversion is kept.
+func (c *Converter) convertWithFallback(entry *FileReport, provider Provider, job fileJob, source string, guidance ...string) (string, error) {
+	type candidate struct {
+		code, provider, model string
+		samples               []SampleScore
+	}
+	var kept *candidate
+	var lastErr error
+
+	names := append([]string{provider.Name()}, c.fallback...)
+	for i, name := range names {
+		p := provider
+		if i > 0 {
+			next, violation, err := c.providerNamed(name, job.relPath, job.sourceLang, source)
+			if err != nil {
+				return "", err
+			}
+			if next == nil {
+				entry.Fallback = append(entry.Fallback, FallbackAttempt{Provider: name, Failure: actionBlocked, Error: violation.String()})
+				continue
+			}
+			p = next
+		}
+
+		var model string
+		code, samples, err := c.convertSampled(modelRecorder{Provider: p, model: &model}, job, source, guidance...)
+		last := i == len(names)-1
+		failure := ""
+		if !last || kept != nil {
+			failure = c.conversionFailure(job, code, err)
+		}
+		if failure == "" || !c.fallsBackOn(failure) {
+			entry.Provider, entry.Model, entry.Samples = p.Name(), model, samples
+			return code, err
+		}
+
+		attempt := FallbackAttempt{Provider: p.Name(), Model: model, Failure: failure}
+		if err != nil {
+			attempt.Error = err.Error()
+			lastErr = err
+		}
+		entry.Fallback = append(entry.Fallback, attempt)
+		if failure == FallbackInvalid && kept == nil {
+			kept = &candidate{code: code, provider: p.Name(), model: model, samples: samples}
+		}
+		if !last {
+			c.logf("%s failed on %s (%s); falling back to %s\n", p.Name(), job.inputPath, failure, names[i+1])
+		}
+	}
+
+	if kept != nil {
+		c.logf("No provider produced valid code for %s; keeping the conversion by %s\n", job.inputPath, kept.provider)
+		entry.Provider, entry.Model, entry.Samples = kept.provider, kept.model, kept.samples
+		return kept.code, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no provider in the fallback chain may receive the file")
+	}
+	return "", lastErr
+}
+
+// fallsBackOn reports whether a failure class moves a file on to the next provider
+func (c *Converter) fallsBackOn(failure string) bool {
+	if c.fallbackOn == nil {
+		return true
+	}
+	return containsString(c.fallbackOn, failure)
+}
+
+// conversionFailure classifies why a conversion failed, or returns "" when it succeeded.
+// Validity is only checked when invalid code is a fallback condition.
+func (c *Converter) conversionFailure(job fileJob, code string, err error) string {
+	if err != nil {
+		return classifyError(err)
+	}
+	if refusalPattern.MatchString(code) {
+		return FallbackRefusal
+	}
+	if !c.fallsBackOn(FallbackInvalid) {
+		return ""
+	}
+	lang, ok := languages.lookup(c.targetLang)
+	if !ok {
+		return ""
+	}
+	if strings.TrimSpace(code) == "" || !checkSyntax(lang, code) {
+		return FallbackInvalid
+	}
+	if result := c.validateContent(job.outputPath, code); !result.Skipped && !result.Passed {
+		return FallbackInvalid
+	}
+	return ""
+}
+
+// classifyError tells rate limits and context overflows apart from other provider errors
+func classifyError(err error) string {
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return FallbackRateLimit
+	case errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusTooManyRequests:
+		return FallbackRateLimit
+	case errors.As(err, &reqErr) && reqErr.HTTPStatusCode == http.StatusTooManyRequests:
+		return FallbackRateLimit
+	case errors.As(err, &apiErr) && apiErr.Code == "context_length_exceeded":
+		return FallbackContext
+	case contextPattern.MatchString(err.Error()):
+		return FallbackContext
+	}
+	return FallbackError
+}
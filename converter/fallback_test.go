@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// TestClassifyError tests telling provider failures apart
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("fake: %w", ErrRateLimited), FallbackRateLimit},
+		{fmt.Errorf("failed to generate text: %w", &openai.APIError{HTTPStatusCode: 429, Message: "slow down"}), FallbackRateLimit},
+		{&openai.RequestError{HTTPStatusCode: 429, Err: errors.New("too many requests")}, FallbackRateLimit},
+		{&openai.APIError{HTTPStatusCode: 400, Code: "context_length_exceeded"}, FallbackContext},
+		{errors.New("This model's maximum context length is 8192 tokens"), FallbackContext},
+		{errors.New("connection refused"), FallbackError},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("classifyError(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestConvertFallback tests moving a file down the fallback chain until a provider succeeds
+func TestConvertFallback(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"math.go": "package math\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+	})
+
+	good := "def add(a, b):\n    return a + b\n"
+	broken := "def add(a, b:\n    return a + b\n"
+	overflowing, _ := NewFakeProvider("test-overflowing", FakeResponse{Error: "this model's maximum context length is 8192 tokens"})
+	refusing, _ := NewFakeProvider("test-refusing", FakeResponse{Response: "I'm sorry, but I can't help with that."})
+	breaking, _ := NewFakeProvider("test-breaking", FakeResponse{Response: broken})
+	for _, p := range []Provider{overflowing, refusing, breaking, pricedProvider{name: "test-local", text: good, model: "local-coder"}} {
+		RegisterProvider(p)
+		defer delete(providers, p.Name())
+	}
+
+	convert := func(fallback, conditions []string) (*Converter, string, error) {
+		out := t.TempDir()
+		c := NewConverter(root, out, "python")
+		c.SetScaffold(false)
+		c.SetProvider("test-overflowing")
+		c.SetFallback(fallback)
+		c.SetFallbackConditions(conditions)
+		c.SetLogOutput(io.Discard)
+		err := c.Convert()
+		data, _ := os.ReadFile(filepath.Join(out, "math.py"))
+		return c, string(data), err
+	}
+
+	c, output, err := convert([]string{"test-refusing", "test-breaking", "test-local"}, nil)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if output != good {
+		t.Errorf("Expected the local model's conversion, got %q", output)
+	}
+	entry := c.Report().Files[0]
+	if entry.Provider != "test-local" || entry.Model != "local-coder" {
+		t.Errorf("Expected the file to be attributed to test-local/local-coder, got %s/%s", entry.Provider, entry.Model)
+	}
+	var failures []string
+	for _, attempt := range entry.Fallback {
+		failures = append(failures, attempt.Provider+":"+attempt.Failure)
+	}
+	if fmt.Sprint(failures) != "[test-overflowing:context test-refusing:refusal test-breaking:invalid]" {
+		t.Errorf("Unexpected fallback attempts: %+v", entry.Fallback)
+	}
+
+	// Failures that are not conditions fail the file as before
+	if _, _, err := convert([]string{"test-local"}, []string{FallbackRateLimit}); err == nil {
+		t.Error("Expected the context overflow to fail the conversion")
+	}
+
+	// When every provider produces invalid code, the first invalid conversion is kept
+	c, output, err = convert([]string{"test-breaking", "test-refusing"}, nil)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if output != broken || c.Report().Files[0].Provider != "test-breaking" {
+		t.Errorf("Expected the invalid conversion to be kept, got %q from %s", output, c.Report().Files[0].Provider)
+	}
+}
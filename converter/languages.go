@@ -0,0 +1,549 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Language describes everything the converter knows about a programming language.
+// Command arguments may contain {file} and {tmp} placeholders for the file being
+// processed and a scratch directory.
+type Language struct {
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Extensions   []string `json:"extensions,omitempty"`
+	Interpreters []string `json:"interpreters,omitempty"`
+	LineComment  string   `json:"line_comment,omitempty"`
+	BlockComment []string `json:"block_comment,omitempty"`
+	TestPatterns []string `json:"test_patterns,omitempty"`
+	Formatter    []string `json:"formatter,omitempty"`
+	Validator    []string `json:"validator,omitempty"`
+	Guidance     string   `json:"guidance,omitempty"`
+	// TestFramework names the idiomatic test framework that converted tests should use
+	TestFramework string `json:"test_framework,omitempty"`
+	// TestPath is where converted tests go, relative to the output root. It may use
+	// {dir} for the tested code's directory, {name} for its file name without the
+	// extension, and {Name} and {snake_name} for PascalCase and snake_case variants.
+	TestPath string `json:"test_path,omitempty"`
+	// Family groups languages that can be modernized into one another, such as
+	// JavaScript and TypeScript; it defaults to the language's own name
+	Family string `json:"family,omitempty"`
+
+	// Run executes a program from its entry file's directory and TestCommand runs a
+	// project's test suite from its root directory; both are used by verify. Either
+	// may start with NAME=value arguments that are set in the command's environment.
+	Run         []string `json:"run,omitempty"`
+	TestCommand []string `json:"test_command,omitempty"`
+
+	// VersionedValidator is used instead of Validator when a target version such
+	// as python@3.12 is requested; {version} is replaced with the version
+	VersionedValidator []string `json:"versioned_validator,omitempty"`
+	// ModifierArgs adds validator arguments for target modifiers such as "strict"
+	ModifierArgs map[string][]string `json:"modifier_args,omitempty"`
+}
+
+// Extension returns the extension used for files written in this language
+func (l *Language) Extension() string {
+	if len(l.Extensions) == 0 {
+		return ""
+	}
+	return l.Extensions[0]
+}
+
+// FamilyName returns the language family used to decide whether a file can be modernized
+func (l *Language) FamilyName() string {
+	return firstNonEmpty(l.Family, l.Name)
+}
+
+// IsTestFile reports whether a path, relative to the input root, matches the
+// language's test-file patterns. Patterns without a slash match the base name.
+func (l *Language) IsTestFile(relPath string) bool {
+	relPath = strings.ReplaceAll(relPath, "\\", "/")
+	for _, pattern := range l.TestPatterns {
+		target := relPath
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(relPath)
+		}
+		if ok, _ := doublestar.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinLanguages are registered in every registry. Where several languages
+// claim an extension, the first one registered is the default for that extension.
+var builtinLanguages = []Language{
+	{
+		Name: "Go", Aliases: []string{"golang"}, Extensions: []string{".go"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*_test.go"},
+		TestFramework: "the standard testing package (func TestXxx(t *testing.T), table-driven where it fits)", TestPath: "{dir}/{snake_name}_test.go",
+		Formatter:   []string{"gofmt", "-w", "{file}"},
+		Validator:   []string{"gofmt", "-e", "-l", "{file}"},
+		Run:         []string{"GO111MODULE=auto", "go", "run", "."},
+		TestCommand: []string{"go", "test", "./..."},
+	},
+	{
+		Name: "JavaScript", Aliases: []string{"js", "node", "nodejs", "ecmascript"}, Family: "JavaScript",
+		Extensions: []string{".js", ".mjs", ".cjs", ".jsx"}, Interpreters: []string{"node"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*.test.js", "*.spec.js", "*.test.jsx", "*.spec.jsx", "**/__tests__/*.js"},
+		TestFramework: "Jest (describe, it and expect)", TestPath: "{dir}/{name}.test.js",
+		Formatter:   []string{"prettier", "--write", "{file}"},
+		Validator:   []string{"node", "--check", "{file}"},
+		Run:         []string{"node", "{file}"},
+		TestCommand: []string{"npx", "jest"},
+	},
+	{
+		// Extensions line up with JavaScript's so modernized files keep their module kind
+		Name: "TypeScript", Aliases: []string{"ts"}, Family: "JavaScript",
+		Extensions: []string{".ts", ".mts", ".cts", ".tsx"}, Interpreters: []string{"deno", "ts-node", "tsx"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*.test.ts", "*.spec.ts", "*.test.tsx", "*.spec.tsx", "**/__tests__/*.ts"},
+		TestFramework: "Vitest (describe, it and expect imported from vitest)", TestPath: "{dir}/{name}.test.ts",
+		Formatter:    []string{"prettier", "--write", "{file}"},
+		Validator:    []string{"tsc", "--noEmit", "--skipLibCheck", "{file}"},
+		ModifierArgs: map[string][]string{"strict": {"--strict"}},
+		Run:          []string{"npx", "tsx", "{file}"},
+		TestCommand:  []string{"npx", "vitest", "run"},
+	},
+	{
+		Name: "Python", Aliases: []string{"py", "python3"},
+		Extensions: []string{".py", ".pyw", ".pyi"}, Interpreters: []string{"python"},
+		LineComment: "#", BlockComment: []string{`"""`, `"""`},
+		TestPatterns:  []string{"test_*.py", "*_test.py"},
+		TestFramework: "pytest (plain assert statements, fixtures and parametrize)", TestPath: "tests/{dir}/test_{snake_name}.py",
+		Formatter:          []string{"black", "-q", "{file}"},
+		Validator:          []string{"python3", "-m", "py_compile", "{file}"},
+		VersionedValidator: []string{"python{version}", "-m", "py_compile", "{file}"},
+		Run:                []string{"python3", "{file}"},
+		TestCommand:        []string{"python3", "-m", "pytest", "-q"},
+	},
+	{
+		Name: "Java", Extensions: []string{".java"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Test.java", "*Tests.java", "**/src/test/**/*.java"},
+		TestFramework: "JUnit 5 (org.junit.jupiter.api)", TestPath: "src/test/java/{dir}/{Name}Test.java",
+		Formatter:          []string{"google-java-format", "-i", "{file}"},
+		Validator:          []string{"javac", "-d", "{tmp}", "{file}"},
+		VersionedValidator: []string{"javac", "--release", "{version}", "-d", "{tmp}", "{file}"},
+		Run:                []string{"java", "{file}"},
+		TestCommand:        []string{"mvn", "-q", "test"},
+	},
+	{
+		Name: "C", Extensions: []string{".c", ".h"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:       []string{"test_*.c", "*_test.c"},
+		Formatter:          []string{"clang-format", "-i", "{file}"},
+		Validator:          []string{"cc", "-fsyntax-only", "{file}"},
+		VersionedValidator: []string{"cc", "-std=c{version}", "-fsyntax-only", "{file}"},
+	},
+	{
+		Name: "C++", Aliases: []string{"cpp", "cxx"},
+		Extensions:  []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx", ".h"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*_test.cpp", "*_test.cc", "test_*.cpp"},
+		TestFramework: "GoogleTest", TestPath: "tests/{dir}/{snake_name}_test.cpp",
+		Formatter:          []string{"clang-format", "-i", "{file}"},
+		Validator:          []string{"c++", "-fsyntax-only", "{file}"},
+		VersionedValidator: []string{"c++", "-std=c++{version}", "-fsyntax-only", "{file}"},
+	},
+	{
+		Name: "C#", Aliases: []string{"csharp", "cs", "dotnet"}, Extensions: []string{".cs"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Tests.cs", "*Test.cs"},
+		TestFramework: "xUnit", TestPath: "tests/{dir}/{Name}Tests.cs",
+		TestCommand: []string{"dotnet", "test"},
+	},
+	{
+		Name: "Ruby", Aliases: []string{"rb"}, Extensions: []string{".rb"}, Interpreters: []string{"ruby"},
+		LineComment: "#", BlockComment: []string{"=begin", "=end"},
+		TestPatterns:  []string{"*_spec.rb", "*_test.rb", "test_*.rb"},
+		TestFramework: "RSpec", TestPath: "spec/{dir}/{snake_name}_spec.rb",
+		Formatter:   []string{"rubocop", "-a", "{file}"},
+		Validator:   []string{"ruby", "-c", "{file}"},
+		Run:         []string{"ruby", "{file}"},
+		TestCommand: []string{"bundle", "exec", "rspec"},
+	},
+	{
+		Name: "PHP", Extensions: []string{".php"}, Interpreters: []string{"php"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Test.php"},
+		TestFramework: "PHPUnit", TestPath: "tests/{dir}/{Name}Test.php",
+		Validator:   []string{"php", "-l", "{file}"},
+		Run:         []string{"php", "{file}"},
+		TestCommand: []string{"vendor/bin/phpunit"},
+	},
+	{
+		Name: "Rust", Aliases: []string{"rs"}, Extensions: []string{".rs"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"tests/*.rs", "**/tests/*.rs"},
+		TestFramework: "Rust's built-in #[test] functions run by cargo test", TestPath: "tests/{snake_name}.rs",
+		Formatter:   []string{"rustfmt", "--edition", "2021", "{file}"},
+		Validator:   []string{"rustfmt", "--edition", "2021", "--emit", "stdout", "{file}"},
+		TestCommand: []string{"cargo", "test", "--quiet"},
+	},
+	{
+		Name: "Swift", Extensions: []string{".swift"}, Interpreters: []string{"swift"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Tests.swift"},
+		TestFramework: "XCTest", TestPath: "Tests/{dir}/{Name}Tests.swift",
+		Formatter:   []string{"swift-format", "-i", "{file}"},
+		Validator:   []string{"swiftc", "-parse", "{file}"},
+		Run:         []string{"swift", "{file}"},
+		TestCommand: []string{"swift", "test"},
+	},
+	{
+		Name: "Kotlin", Aliases: []string{"kt"}, Extensions: []string{".kt", ".kts"}, Interpreters: []string{"kotlin"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Test.kt", "**/src/test/**/*.kt"},
+		TestFramework: "JUnit 5 with kotlin.test assertions", TestPath: "src/test/kotlin/{dir}/{Name}Test.kt",
+		Formatter:   []string{"ktlint", "-F", "{file}"},
+		TestCommand: []string{"gradle", "test"},
+	},
+	{
+		Name: "Scala", Extensions: []string{".scala", ".sc"}, Interpreters: []string{"scala"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Spec.scala", "*Test.scala", "*Suite.scala"},
+		TestFramework: "ScalaTest", TestPath: "src/test/scala/{dir}/{Name}Spec.scala",
+		Formatter:   []string{"scalafmt", "{file}"},
+		Guidance:    "Write Scala 3, prefer immutable vals, case classes and Option over null.",
+		Run:         []string{"scala", "{file}"},
+		TestCommand: []string{"sbt", "test"},
+	},
+	{
+		Name: "Elixir", Aliases: []string{"ex"}, Extensions: []string{".ex", ".exs"}, Interpreters: []string{"elixir"},
+		LineComment:   "#",
+		TestPatterns:  []string{"*_test.exs"},
+		TestFramework: "ExUnit", TestPath: "test/{dir}/{snake_name}_test.exs",
+		Formatter:   []string{"mix", "format", "{file}"},
+		Guidance:    "Organise code in modules with pattern-matched function clauses and return {:ok, value} or {:error, reason} tuples instead of raising.",
+		Run:         []string{"elixir", "{file}"},
+		TestCommand: []string{"mix", "test"},
+	},
+	{
+		Name: "Dart", Extensions: []string{".dart"}, Interpreters: []string{"dart"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*_test.dart"},
+		TestFramework: "package:test", TestPath: "test/{dir}/{snake_name}_test.dart",
+		Formatter:   []string{"dart", "format", "{file}"},
+		Validator:   []string{"dart", "analyze", "{file}"},
+		Guidance:    "Use sound null safety, final for values that never change, and async/await with Future for asynchronous code.",
+		Run:         []string{"dart", "run", "{file}"},
+		TestCommand: []string{"dart", "test"},
+	},
+	{
+		Name: "Lua", Extensions: []string{".lua"}, Interpreters: []string{"lua", "luajit"},
+		LineComment: "--", BlockComment: []string{"--[[", "]]"},
+		TestPatterns:  []string{"*_spec.lua", "test_*.lua"},
+		TestFramework: "busted", TestPath: "spec/{dir}/{snake_name}_spec.lua",
+		Formatter:   []string{"stylua", "{file}"},
+		Validator:   []string{"luac", "-p", "{file}"},
+		Guidance:    "Use local variables, tables with metatables for objects, and return a module table at the end of the file.",
+		Run:         []string{"lua", "{file}"},
+		TestCommand: []string{"busted"},
+	},
+	{
+		Name: "Haskell", Aliases: []string{"hs"}, Extensions: []string{".hs", ".lhs"}, Interpreters: []string{"runghc", "runhaskell"},
+		LineComment: "--", BlockComment: []string{"{-", "-}"},
+		TestPatterns:  []string{"*Spec.hs", "**/test/**/*.hs"},
+		TestFramework: "Hspec", TestPath: "test/{dir}/{Name}Spec.hs",
+		Formatter:   []string{"ormolu", "--mode", "inplace", "{file}"},
+		Validator:   []string{"ghc", "-fno-code", "{file}"},
+		Guidance:    "Write pure functions with explicit type signatures, keep side effects in IO, and use Maybe or Either for failure.",
+		Run:         []string{"runghc", "{file}"},
+		TestCommand: []string{"cabal", "test"},
+	},
+	{
+		Name: "Perl", Aliases: []string{"pl"}, Extensions: []string{".pl", ".pm", ".t"}, Interpreters: []string{"perl"},
+		LineComment: "#", BlockComment: []string{"=pod", "=cut"},
+		TestPatterns:  []string{"*.t"},
+		TestFramework: "Test::More", TestPath: "t/{dir}/{snake_name}.t",
+		Formatter:   []string{"perltidy", "-b", "{file}"},
+		Validator:   []string{"perl", "-c", "{file}"},
+		Guidance:    "Start every file with use strict; and use warnings;, use lexical my variables, and put reusable code in packages.",
+		Run:         []string{"perl", "{file}"},
+		TestCommand: []string{"prove", "-r", "t"},
+	},
+	{
+		Name: "R", Aliases: []string{"rlang"}, Extensions: []string{".R"}, Interpreters: []string{"Rscript"},
+		LineComment:   "#",
+		TestPatterns:  []string{"test-*.[rR]", "test_*.[rR]"},
+		TestFramework: "testthat", TestPath: "tests/testthat/test-{name}.R",
+		Validator:   []string{"Rscript", "-e", "invisible(parse('{file}'))"},
+		Guidance:    "Prefer vectorised operations to loops, use <- for assignment, and represent records as lists or data frames.",
+		Run:         []string{"Rscript", "{file}"},
+		TestCommand: []string{"Rscript", "-e", "testthat::test_dir('tests/testthat')"},
+	},
+	{
+		Name: "Objective-C", Aliases: []string{"objc", "objectivec", "obj-c"}, Extensions: []string{".m", ".mm", ".h"},
+		LineComment: "//", BlockComment: []string{"/*", "*/"},
+		TestPatterns:  []string{"*Tests.m"},
+		TestFramework: "XCTest", TestPath: "Tests/{dir}/{Name}Tests.m",
+		Formatter: []string{"clang-format", "-i", "{file}"},
+		Validator: []string{"clang", "-fsyntax-only", "-x", "objective-c", "{file}"},
+		Guidance:  "Split declarations and definitions into @interface and @implementation, rely on ARC, and use Foundation types such as NSString and NSArray.",
+	},
+	{
+		Name: "Shell", Aliases: []string{"bash", "sh", "zsh"}, Extensions: []string{".sh", ".bash", ".zsh"},
+		Interpreters:  []string{"sh", "bash", "zsh", "ksh", "dash"},
+		LineComment:   "#",
+		TestPatterns:  []string{"*.bats", "test_*.sh"},
+		TestFramework: "bats", TestPath: "test/{dir}/{name}.bats",
+		Formatter:   []string{"shfmt", "-w", "{file}"},
+		Validator:   []string{"bash", "-n", "{file}"},
+		Guidance:    "Write bash with set -euo pipefail, quote every variable expansion, and wrap reusable logic in functions.",
+		Run:         []string{"bash", "{file}"},
+		TestCommand: []string{"bats", "test"},
+	},
+	{
+		Name: "PowerShell", Aliases: []string{"pwsh", "ps1", "posh"}, Extensions: []string{".ps1", ".psm1", ".psd1"},
+		Interpreters: []string{"pwsh", "powershell"},
+		LineComment:  "#", BlockComment: []string{"<#", "#>"},
+		TestPatterns:  []string{"*.Tests.ps1"},
+		TestFramework: "Pester", TestPath: "tests/{dir}/{Name}.Tests.ps1",
+		Guidance:    "Use approved Verb-Noun function names, param() blocks with typed parameters, and emit objects to the pipeline rather than formatted text.",
+		Run:         []string{"pwsh", "-NoProfile", "-File", "{file}"},
+		TestCommand: []string{"pwsh", "-NoProfile", "-Command", "Invoke-Pester"},
+	},
+}
+
+// registry indexes languages by name, alias, extension and interpreter
+type registry struct {
+	languages     []*Language
+	byName        map[string]*Language
+	byExtension   map[string][]*Language
+	byInterpreter map[string]*Language
+}
+
+// languages is the registry shared by detection, output naming and the CLI
+var languages = newRegistry(builtinLanguages)
+
+func newRegistry(langs []Language) *registry {
+	r := &registry{}
+	for _, lang := range langs {
+		r.register(lang)
+	}
+	return r
+}
+
+// register adds a language, or merges it into an existing language of the same
+// name: list fields are extended and non-empty settings replace the old ones
+func (r *registry) register(lang Language) {
+	if existing, ok := r.lookup(lang.Name); ok && strings.EqualFold(existing.Name, lang.Name) {
+		existing.Aliases = appendUnique(existing.Aliases, lang.Aliases...)
+		existing.Extensions = appendUnique(existing.Extensions, lang.Extensions...)
+		existing.Interpreters = appendUnique(existing.Interpreters, lang.Interpreters...)
+		existing.TestPatterns = appendUnique(existing.TestPatterns, lang.TestPatterns...)
+		existing.LineComment = firstNonEmpty(lang.LineComment, existing.LineComment)
+		if len(lang.BlockComment) == 2 {
+			existing.BlockComment = lang.BlockComment
+		}
+		if len(lang.Formatter) > 0 {
+			existing.Formatter = lang.Formatter
+		}
+		if len(lang.Validator) > 0 {
+			existing.Validator = lang.Validator
+		}
+		existing.Guidance = firstNonEmpty(lang.Guidance, existing.Guidance)
+		existing.Family = firstNonEmpty(lang.Family, existing.Family)
+		existing.TestFramework = firstNonEmpty(lang.TestFramework, existing.TestFramework)
+		existing.TestPath = firstNonEmpty(lang.TestPath, existing.TestPath)
+		if len(lang.Run) > 0 {
+			existing.Run = lang.Run
+		}
+		if len(lang.TestCommand) > 0 {
+			existing.TestCommand = lang.TestCommand
+		}
+		if len(lang.VersionedValidator) > 0 {
+			existing.VersionedValidator = lang.VersionedValidator
+		}
+		for modifier, args := range lang.ModifierArgs {
+			if existing.ModifierArgs == nil {
+				existing.ModifierArgs = map[string][]string{}
+			}
+			existing.ModifierArgs[modifier] = args
+		}
+	} else {
+		copied := lang
+		r.languages = append(r.languages, &copied)
+	}
+	r.reindex()
+}
+
+// reindex rebuilds the lookup maps after languages change
+func (r *registry) reindex() {
+	r.byName = map[string]*Language{}
+	r.byExtension = map[string][]*Language{}
+	r.byInterpreter = map[string]*Language{}
+	for _, lang := range r.languages {
+		r.byName[strings.ToLower(lang.Name)] = lang
+		for _, alias := range lang.Aliases {
+			r.byName[strings.ToLower(alias)] = lang
+		}
+		for _, ext := range lang.Extensions {
+			ext = strings.ToLower(ext)
+			if !containsLanguage(r.byExtension[ext], lang) {
+				r.byExtension[ext] = append(r.byExtension[ext], lang)
+			}
+		}
+		for _, interpreter := range lang.Interpreters {
+			r.byInterpreter[interpreter] = lang
+		}
+	}
+}
+
+// lookup finds a language by name, alias or extension, ignoring case
+func (r *registry) lookup(name string) (*Language, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if lang, ok := r.byName[name]; ok {
+		return lang, true
+	}
+	if langs := r.byExtension["."+strings.TrimPrefix(name, ".")]; len(langs) > 0 {
+		return langs[0], true
+	}
+	return nil, false
+}
+
+// suggest returns known names within a small edit distance of an unknown name
+func (r *registry) suggest(name string) []string {
+	name = strings.ToLower(name)
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	var candidates []candidate
+	for key := range r.byName {
+		d := editDistance(name, key)
+		if d <= 2 || (len(name) >= 2 && strings.HasPrefix(key, name)) {
+			candidates = append(candidates, candidate{key, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
+
+// LookupLanguage finds a registered language by name, alias or extension
+func LookupLanguage(name string) (*Language, bool) {
+	return languages.lookup(name)
+}
+
+// ResolveLanguage is LookupLanguage with an error that suggests close matches
+func ResolveLanguage(name string) (*Language, error) {
+	if lang, ok := languages.lookup(name); ok {
+		return lang, nil
+	}
+
+	msg := fmt.Sprintf("unknown language %q", name)
+	if suggestions := languages.suggest(name); len(suggestions) > 0 {
+		msg += fmt.Sprintf("; did you mean %s?", strings.Join(suggestions, ", "))
+	}
+	return nil, fmt.Errorf("%s (run the languages command to list supported languages)", msg)
+}
+
+// Languages returns every registered language in registration order
+func Languages() []*Language {
+	return append([]*Language(nil), languages.languages...)
+}
+
+// RegisterLanguage adds a language or extends the built-in definition with the same name
+func RegisterLanguage(lang Language) error {
+	if lang.Name == "" {
+		return fmt.Errorf("language definition has no name")
+	}
+	languages.register(lang)
+	return nil
+}
+
+// LoadLanguageConfig registers the languages defined in a JSON file containing an array of Language objects
+func LoadLanguageConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read language config %s: %w", path, err)
+	}
+
+	var langs []Language
+	if err := json.Unmarshal(data, &langs); err != nil {
+		return fmt.Errorf("failed to parse language config %s: %w", path, err)
+	}
+	for _, lang := range langs {
+		if err := RegisterLanguage(lang); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// expandCommand substitutes the {file} and {tmp} placeholders in a command
+func expandCommand(args []string, file, tmp string) []string {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "{file}", file)
+		expanded[i] = strings.ReplaceAll(arg, "{tmp}", tmp)
+	}
+	return expanded
+}
+
+func containsLanguage(langs []*Language, lang *Language) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, extra ...string) []string {
+	for _, v := range extra {
+		found := false
+		for _, existing := range values {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
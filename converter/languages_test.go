@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLookupLanguage tests that names, aliases and extensions resolve to registered languages
+func TestLookupLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"go", "Go"},
+		{"golang", "Go"},
+		{"Python", "Python"},
+		{"py", "Python"},
+		{"node", "JavaScript"},
+		{".rs", "Rust"},
+		{"c#", "C#"},
+		{"csharp", "C#"},
+	}
+
+	for _, tt := range tests {
+		if got, ok := LookupLanguage(tt.name); !ok || got.Name != tt.want {
+			t.Errorf("LookupLanguage(%q) = (%v, %v), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+// TestResolveLanguageSuggestions tests that unknown languages produce an error with close matches
+func TestResolveLanguageSuggestions(t *testing.T) {
+	_, err := ResolveLanguage("pyton")
+	if err == nil {
+		t.Fatalf("ResolveLanguage(%q) unexpectedly succeeded", "pyton")
+	}
+	if !strings.Contains(err.Error(), "did you mean python") {
+		t.Errorf("ResolveLanguage(%q) error = %v, want a suggestion for python", "pyton", err)
+	}
+
+	if _, err := ResolveLanguage("cobolish"); err == nil {
+		t.Errorf("ResolveLanguage(%q) unexpectedly succeeded", "cobolish")
+	}
+}
+
+// TestIsTestFile tests per-language test-file patterns
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		lang    string
+		relPath string
+		want    bool
+	}{
+		{"go", "pkg/server_test.go", true},
+		{"go", "pkg/server.go", false},
+		{"python", "tests/test_server.py", true},
+		{"typescript", "src/app.spec.ts", true},
+		{"java", "src/test/java/com/acme/Helper.java", true},
+		{"java", "src/main/java/com/acme/Helper.java", false},
+		{"rust", "tests/integration.rs", true},
+	}
+
+	for _, tt := range tests {
+		lang, _ := LookupLanguage(tt.lang)
+		if got := lang.IsTestFile(tt.relPath); got != tt.want {
+			t.Errorf("%s IsTestFile(%q) = %v, want %v", lang.Name, tt.relPath, got, tt.want)
+		}
+	}
+}
+
+// TestLoadLanguageConfig tests that a config file can add languages and extend built-in ones
+func TestLoadLanguageConfig(t *testing.T) {
+	saved := languages
+	languages = newRegistry(builtinLanguages)
+	defer func() { languages = saved }()
+
+	config := `[
+		{"name": "Zig", "aliases": ["ziglang"], "extensions": [".zig"], "line_comment": "//"},
+		{"name": "Python", "extensions": [".pyx"]}
+	]`
+	path := filepath.Join(t.TempDir(), "languages.json")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := LoadLanguageConfig(path); err != nil {
+		t.Fatalf("LoadLanguageConfig() error = %v", err)
+	}
+
+	if ext := getTargetExtension("ziglang"); ext != ".zig" {
+		t.Errorf("getTargetExtension(%q) = %q, want %q", "ziglang", ext, ".zig")
+	}
+	if lang, ok := detectLanguage("module.pyx"); !ok || lang != "Python" {
+		t.Errorf("detectLanguage(%q) = (%q, %v), want (%q, true)", "module.pyx", lang, ok, "Python")
+	}
+	if ext := getTargetExtension("python"); ext != ".py" {
+		t.Errorf("getTargetExtension(%q) = %q, want %q after extending Python", "python", ext, ".py")
+	}
+}
+
+// TestAdditionalLanguages tests detection and output extensions for the less common languages
+func TestAdditionalLanguages(t *testing.T) {
+	tests := []struct {
+		file    string
+		want    string
+		target  string
+		wantExt string
+	}{
+		{"Main.scala", "Scala", "scala", ".scala"},
+		{"lib/app.ex", "Elixir", "elixir", ".ex"},
+		{"main.dart", "Dart", "dart", ".dart"},
+		{"init.lua", "Lua", "lua", ".lua"},
+		{"Main.hs", "Haskell", "haskell", ".hs"},
+		{"script.pl", "Perl", "perl", ".pl"},
+		{"analysis.R", "R", "r", ".R"},
+		{"AppDelegate.m", "Objective-C", "objc", ".m"},
+		{"deploy.sh", "Shell", "bash", ".sh"},
+		{"Build.ps1", "PowerShell", "pwsh", ".ps1"},
+	}
+
+	for _, tt := range tests {
+		if got, ok := detectLanguage(tt.file); !ok || got != tt.want {
+			t.Errorf("detectLanguage(%q) = (%q, %v), want (%q, true)", tt.file, got, ok, tt.want)
+		}
+		if ext := getTargetExtension(tt.target); ext != tt.wantExt {
+			t.Errorf("getTargetExtension(%q) = %q, want %q", tt.target, ext, tt.wantExt)
+		}
+	}
+}
+
+// TestTargetGuidanceInPrompt tests that language-specific guidance is sent with the conversion prompt
+func TestTargetGuidanceInPrompt(t *testing.T) {
+	var prompt string
+	defer setupMockProvider(func(p string) (string, error) {
+		prompt = p
+		return "converted", nil
+	})()
+
+	if _, _, err := NewConverter("in", "out", "bash").convertCode(defaultProvider(), "print('hi')", "Python", "hi.py"); err != nil {
+		t.Fatalf("convertCode() error = %v", err)
+	}
+	if !strings.Contains(prompt, "set -euo pipefail") {
+		t.Errorf("Prompt does not include Shell guidance:\n%s", prompt)
+	}
+}
@@ -0,0 +1,260 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Layout controls how converted files are arranged in the output directory
+type Layout string
+
+const (
+	// LayoutMirror reproduces the source tree and only swaps file extensions
+	LayoutMirror Layout = "mirror"
+	// LayoutIdiomatic applies the target language's directory and file naming conventions
+	LayoutIdiomatic Layout = "idiomatic"
+	// LayoutFlat writes every file directly into the output directory
+	LayoutFlat Layout = "flat"
+)
+
+// ParseLayout validates a layout name given on the command line
+func ParseLayout(name string) (Layout, error) {
+	switch layout := Layout(strings.ToLower(name)); layout {
+	case LayoutMirror, LayoutIdiomatic, LayoutFlat:
+		return layout, nil
+	}
+	return "", fmt.Errorf("unknown layout %q (expected mirror, idiomatic or flat)", name)
+}
+
+// layoutRule describes the idiomatic layout for a target language. Packages follow
+// the source directories: files are renamed and their directories recased, but files
+// are never regrouped, so a Go package is every file converted from one source directory.
+type layoutRule struct {
+	root      []string
+	dirCase   func(string) string
+	fileCase  func(string) string
+	packageOf func(dirs []string) string
+}
+
+// layoutRules holds the idiomatic layout conventions, keyed by target extension
+var layoutRules = map[string]layoutRule{
+	".py":    {dirCase: snakeCase, fileCase: snakeCase},
+	".rb":    {dirCase: snakeCase, fileCase: snakeCase},
+	".rs":    {dirCase: snakeCase, fileCase: snakeCase},
+	".go":    {dirCase: flatLowerCase, fileCase: snakeCase, packageOf: goPackage},
+	".java":  {root: []string{"src", "main", "java"}, dirCase: flatLowerCase, fileCase: pascalCase, packageOf: jvmPackage},
+	".kt":    {root: []string{"src", "main", "kotlin"}, dirCase: flatLowerCase, fileCase: pascalCase, packageOf: jvmPackage},
+	".cs":    {dirCase: pascalCase, fileCase: pascalCase},
+	".php":   {dirCase: pascalCase, fileCase: pascalCase},
+	".swift": {dirCase: pascalCase, fileCase: pascalCase},
+	".js":    {dirCase: kebabCase, fileCase: kebabCase},
+	".ts":    {dirCase: kebabCase, fileCase: kebabCase},
+}
+
+// SetLayout selects how converted files are arranged in the output directory
+func (c *Converter) SetLayout(layout Layout) {
+	c.layout = layout
+}
+
+// outputPathFor maps a path relative to the input root onto the output tree
+func (c *Converter) outputPathFor(relPath string, converted bool) string {
+	dir, name := filepath.Split(relPath)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if converted {
+		if newExt := getTargetExtension(c.targetLang); newExt != "" {
+			ext = newExt
+		}
+	}
+
+	switch c.layout {
+	case LayoutFlat:
+		return filepath.Join(c.outputDir, stem+ext)
+	case LayoutIdiomatic:
+		if rule, ok := layoutRules[ext]; ok && converted {
+			dirs := idiomaticDirs(rule, splitDir(dir))
+			return filepath.Join(c.outputDir, filepath.Join(append(rule.root, dirs...)...), rule.fileCase(stem)+ext)
+		}
+	}
+	return filepath.Join(c.outputDir, dir, stem+ext)
+}
+
+// layoutGuidance returns prompt instructions that keep the converted code consistent with its output location
+func (c *Converter) layoutGuidance(relPath string) []string {
+	if c.layout != LayoutIdiomatic {
+		return nil
+	}
+
+	ext := getTargetExtension(c.targetLang)
+	rule, ok := layoutRules[ext]
+	if !ok || rule.packageOf == nil {
+		return nil
+	}
+
+	var guidance []string
+	dirs := idiomaticDirs(rule, splitDir(filepath.Dir(relPath)))
+	if pkg := rule.packageOf(dirs); pkg != "" {
+		guidance = append(guidance, fmt.Sprintf("Declare the code in package %s.", pkg))
+	}
+	if ext == ".java" || ext == ".kt" {
+		stem := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+		guidance = append(guidance, fmt.Sprintf("Name the primary public class %s to match the file name.", pascalCase(stem)))
+	}
+	return guidance
+}
+
+func idiomaticDirs(rule layoutRule, dirs []string) []string {
+	out := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		out = append(out, rule.dirCase(d))
+	}
+	return out
+}
+
+// splitDir splits a relative directory into its elements, ignoring "." and empty parts
+func splitDir(dir string) []string {
+	var parts []string
+	for _, p := range strings.Split(filepath.ToSlash(filepath.Clean(dir)), "/") {
+		if p != "" && p != "." {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func goPackage(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	return dirs[len(dirs)-1]
+}
+
+func jvmPackage(dirs []string) string {
+	return strings.Join(dirs, ".")
+}
+
+// writePackageMarkers creates files such as __init__.py and mod.rs that make output directories importable
+func (c *Converter) writePackageMarkers() error {
+	if c.layout != LayoutIdiomatic {
+		return nil
+	}
+
+	ext := getTargetExtension(c.targetLang)
+	if ext != ".py" && ext != ".rs" {
+		return nil
+	}
+
+	// Collect the modules and child packages of every directory below the output root
+	modules := map[string]map[string]bool{}
+	for _, f := range c.converted {
+		dir := filepath.Dir(f.outputPath)
+		child := strings.TrimSuffix(filepath.Base(f.outputPath), ext)
+		for dir != c.outputDir && withinDir(c.outputDir, dir) {
+			if modules[dir] == nil {
+				modules[dir] = map[string]bool{}
+			}
+			modules[dir][child] = true
+			child = filepath.Base(dir)
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	dirs := make([]string, 0, len(modules))
+	for dir := range modules {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if ext == ".py" {
+			if err := c.writeIfAbsent(filepath.Join(dir, "__init__.py"), ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var names []string
+		for name := range modules[dir] {
+			if name != "mod" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		var b strings.Builder
+		for _, name := range names {
+			fmt.Fprintf(&b, "pub mod %s;\n", name)
+		}
+		if err := c.writeIfAbsent(filepath.Join(dir, "mod.rs"), b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// splitWords breaks an identifier into lowercase words at case changes and separators
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(s)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Split on "fooBar" and on the last capital of an acronym in "HTTPServer"
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+func snakeCase(s string) string {
+	// Names that are already snake_case, such as __init__, are kept verbatim
+	if snakeCasePattern.MatchString(s) {
+		return s
+	}
+	name := strings.Join(splitWords(s), "_")
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+func kebabCase(s string) string {
+	return strings.Join(splitWords(s), "-")
+}
+
+func flatLowerCase(s string) string {
+	return strings.Join(splitWords(s), "")
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range splitWords(s) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
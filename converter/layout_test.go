@@ -0,0 +1,129 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestOutputPathFor tests output path computation for each layout
+func TestOutputPathFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		layout     Layout
+		targetLang string
+		relPath    string
+		converted  bool
+		want       string
+	}{
+		{"mirror", LayoutMirror, "python", "pkg/my-module.go", true, "pkg/my-module.py"},
+		{"flat", LayoutFlat, "python", "pkg/sub/util.go", true, "util.py"},
+		{"idiomatic python", LayoutIdiomatic, "python", "my-pkg/HTTPServer.go", true, "my_pkg/http_server.py"},
+		{"idiomatic python keeps dunder", LayoutIdiomatic, "python", "pkg/__init__.py", true, "pkg/__init__.py"},
+		{"idiomatic java", LayoutIdiomatic, "java", "net/http_client.py", true, "src/main/java/net/HttpClient.java"},
+		{"idiomatic go", LayoutIdiomatic, "go", "my_utils/stringHelpers.py", true, "myutils/string_helpers.go"},
+		{"idiomatic copy keeps path", LayoutIdiomatic, "java", "assets/Logo.PNG", false, "assets/Logo.PNG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConverter("in", "out", tt.targetLang)
+			c.SetLayout(tt.layout)
+
+			got := c.outputPathFor(tt.relPath, tt.converted)
+			want := filepath.Join("out", filepath.FromSlash(tt.want))
+			if got != want {
+				t.Errorf("outputPathFor(%q) = %q, want %q", tt.relPath, got, want)
+			}
+		})
+	}
+}
+
+// TestLayoutGuidance tests that idiomatic JVM layouts tell the model which package to declare
+func TestLayoutGuidance(t *testing.T) {
+	c := NewConverter("in", "out", "java")
+	c.SetLayout(LayoutIdiomatic)
+
+	got := c.layoutGuidance("net/http_client.py")
+	want := []string{
+		"Declare the code in package net.",
+		"Name the primary public class HttpClient to match the file name.",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("layoutGuidance() = %q, want %q", got, want)
+	}
+}
+
+// TestIdiomaticPythonPackageMarkers tests that __init__.py files are generated for converted packages
+func TestIdiomaticPythonPackageMarkers(t *testing.T) {
+	tempInput := t.TempDir()
+	tempOutput := t.TempDir()
+
+	subDir := filepath.Join(tempInput, "my-pkg", "inner")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subDir, "Util.go"), []byte("package inner"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cleanup := setupMockGPT("# Converted code", nil)
+	defer cleanup()
+
+	c := NewConverter(tempInput, tempOutput, "python")
+	c.SetLayout(LayoutIdiomatic)
+	c.SetScaffold(false)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	for _, path := range []string{
+		"my_pkg/inner/util.py",
+		"my_pkg/inner/__init__.py",
+		"my_pkg/__init__.py",
+	} {
+		if _, err := os.Stat(filepath.Join(tempOutput, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Expected %s to exist: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempOutput, "__init__.py")); err == nil {
+		t.Errorf("Did not expect __init__.py in the output root")
+	}
+}
+
+// TestPackageMarkersUncleanOutput tests that an output directory given with a trailing
+// separator, or with a sibling sharing its prefix, gets no markers outside its packages
+func TestPackageMarkersUncleanOutput(t *testing.T) {
+	tempInput := t.TempDir()
+	parent := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempInput, "pkg"), 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempInput, "pkg", "util.go"), []byte("package pkg"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cleanup := setupMockGPT("# Converted code", nil)
+	defer cleanup()
+
+	output := filepath.Join(parent, "out")
+	c := NewConverter(tempInput, output+string(filepath.Separator)+"."+string(filepath.Separator), "python")
+	c.SetLayout(LayoutIdiomatic)
+	c.SetScaffold(false)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(output, "pkg", "__init__.py")); err != nil {
+		t.Errorf("Expected pkg/__init__.py to exist: %v", err)
+	}
+	for _, path := range []string{filepath.Join(output, "__init__.py"), filepath.Join(parent, "__init__.py")} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("Did not expect %s", path)
+		}
+	}
+
+	if withinDir(output, output+"2") {
+		t.Errorf("Expected %s2 not to lie within %s", output, output)
+	}
+}
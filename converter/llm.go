@@ -8,33 +8,43 @@ import (
 	openai "github.com/sashabaranov/go-openai"
 )
 
-// Define GenerateText as a variable that holds a function
-var GenerateText = generateTextImpl
+// OpenAIModel is the model the built-in openai provider sends prompts to
+var OpenAIModel = openai.GPT4o
 
-// The actual implementation is now in this function
-func generateTextImpl(prompt string) (string, error) {
+// GenerateText sends a prompt to the default provider, which RegisterProvider can replace
+func GenerateText(prompt string) (string, error) {
+	return defaultProvider().Generate(prompt)
+}
+
+// openAICompletion sends a prompt to OpenAI with the given parameters and returns
+// the cleaned response with its model and usage
+func openAICompletion(prompt string, params ModelParams) (Completion, error) {
 	client := openai.NewClient(
 		os.Getenv("OPENAI_API_KEY"),
 	)
-	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-		Model: openai.GPT4o,
+	req := openai.ChatCompletionRequest{
+		Model: OpenAIModel,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleUser,
 				Content: prompt,
 			},
 		},
-	})
+	}
+	params.apply(&req)
+	resp, err := client.CreateChatCompletion(context.Background(), req)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate text: %w", err)
+		return Completion{}, fmt.Errorf("failed to generate text: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return Completion{}, fmt.Errorf("failed to generate text: no choices returned")
 	}
 
 	// clean the code.
 	code := resp.Choices[0].Message.Content
 	// Remove first and last lines of code.
 	cleanedCode := removeFirstAndLastLines(code)
-	fmt.Println(cleanedCode)
-	return cleanedCode, nil
+	return Completion{Text: cleanedCode, Model: resp.Model, Params: params.record(), Usage: usageOf(resp.Usage)}, nil
 }
 
 func removeFirstAndLastLines(code string) string {
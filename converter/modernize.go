@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetWriteInPlace makes Modernize rewrite the source files instead of writing a patch
+func (c *Converter) SetWriteInPlace(enabled bool) {
+	c.writeInPlace = enabled
+}
+
+// Modernize upgrades every input file that belongs to the target language's
+// family, such as Python 2 to python@3.12 or JavaScript to TypeScript. Changes
+// are written to w as a unified diff relative to the inputs' common root, or
+// applied to the files in place after SetWriteInPlace(true). Files in other
+// languages and files the model leaves as they are stay untouched.
+func (c *Converter) Modernize(w io.Writer) error {
+	target, err := ResolveLanguage(c.targetLang)
+	if err != nil {
+		return fmt.Errorf("target language: %w", err)
+	}
+
+	// Output paths only matter for conversions, so no collision check is needed here
+	jobs, err := c.collectJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		lang, ok := languages.lookup(job.sourceLang)
+		if !job.convert || !ok || lang.FamilyName() != target.FamilyName() {
+			continue
+		}
+		source := filepath.ToSlash(job.relPath)
+		c.observer.FileStarted(source)
+		if err := c.modernizeFile(job, lang, target, w); err != nil {
+			c.observer.FileFailed(source, err)
+			return err
+		}
+	}
+
+	if !c.writeInPlace {
+		c.logf("Patch paths are relative to %s\n", c.inputDir)
+	}
+	return c.writeReport(actionModernized, actionUnchanged, actionSkipped)
+}
+
+// modernizeFile upgrades a single file, writing its patch to w or rewriting it in place
+func (c *Converter) modernizeFile(job fileJob, lang, target *Language, w io.Writer) error {
+	info, err := os.Stat(job.inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to access file %s: %w", job.inputPath, err)
+	}
+	content, err := os.ReadFile(job.inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", job.inputPath, err)
+	}
+	source := string(content)
+
+	newPath := modernizedPath(job.inputPath, lang, target)
+	newRel := filepath.Join(filepath.Dir(job.relPath), filepath.Base(newPath))
+
+	entry := c.newFileReport(job, actionModernized)
+	entry.Output = filepath.ToSlash(newRel)
+	entry.From = describeSource(job.sourceLang, detectDialect(job.sourceLang, source))
+	entry.To = c.target.Describe()
+
+	if newPath != job.inputPath {
+		if _, err := os.Stat(newPath); err == nil {
+			c.logf("Skipping %s: %s already exists\n", job.inputPath, newPath)
+			entry.Action = actionSkipped
+			c.addFile(entry)
+			return nil
+		}
+	}
+
+	provider, violation, err := c.providerFor(job.relPath, job.sourceLang, source)
+	if err != nil {
+		return err
+	}
+	entry.Egress = violation
+	redacted, redactions, blocked := c.redact(source, job.inputPath)
+	entry.Redactions = redactions
+	if provider == nil || blocked {
+		entry.Action = actionBlocked
+		c.addFile(entry)
+		return nil
+	}
+	entry.Provider = provider.Name()
+
+	c.logf("Modernizing %s from %s to %s\n", job.inputPath, entry.From, entry.To)
+	modernized, err := modernizeUsingLLM(provider, redacted, entry.From, entry.To, c.promptGuidance(secretGuidance(redactions)...)...)
+	if err != nil {
+		return fmt.Errorf("failed to modernize %s: %w", job.inputPath, err)
+	}
+	modernized = restoreSecrets(modernized, redactions)
+	c.warnUnrestored(job.inputPath, redactions)
+	entry.Usage = c.usageFor(job.relPath)
+	// Models tend to drop the final newline, which would otherwise show up in every patch
+	if strings.HasSuffix(source, "\n") && !strings.HasSuffix(modernized, "\n") {
+		modernized += "\n"
+	}
+
+	if newPath == job.inputPath && strings.TrimRight(modernized, " \t\r\n") == strings.TrimRight(source, " \t\r\n") {
+		c.logf("No changes needed for %s\n", job.inputPath)
+		entry.Action = actionUnchanged
+		c.addFile(entry)
+		return nil
+	}
+
+	if c.writeInPlace {
+		if err := os.WriteFile(newPath, []byte(modernized), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", newPath, err)
+		}
+		if newPath != job.inputPath {
+			if err := os.Remove(job.inputPath); err != nil {
+				return fmt.Errorf("failed to remove %s after renaming it to %s: %w", job.inputPath, newPath, err)
+			}
+		}
+	} else if _, err := io.WriteString(w, modernizePatch(job.relPath, newRel, source, modernized)); err != nil {
+		return fmt.Errorf("failed to write patch for %s: %w", job.inputPath, err)
+	}
+
+	if c.validate {
+		var result ValidationResult
+		if c.writeInPlace {
+			result = c.validateFile(newPath)
+		} else {
+			result = c.validateContent(newPath, modernized)
+		}
+		entry.Validation = &result
+		if !result.Passed && !result.Skipped {
+			c.logf("Validation failed for %s (%s):\n%s\n", newPath, result.Tool, result.Output)
+		}
+	}
+	c.addFile(entry)
+	return nil
+}
+
+// modernizePatch renders the change to one file. A renamed file, such as app.js
+// becoming app.ts, is expressed as a deletion followed by a creation so the patch
+// applies with both git apply and patch -p1.
+func modernizePatch(oldRel, newRel, source, modernized string) string {
+	oldRel, newRel = filepath.ToSlash(oldRel), filepath.ToSlash(newRel)
+	if oldRel == newRel {
+		return unifiedDiff(oldRel, newRel, source, modernized)
+	}
+	return unifiedDiff(oldRel, "", source, "") + unifiedDiff("", newRel, "", modernized)
+}
+
+// modernizedPath returns where a modernized file lives. Files keep their path
+// unless the target language does not use their extension, in which case the
+// extension at the same position in the target's list is used, so app.mjs
+// becomes app.mts and view.jsx becomes view.tsx.
+func modernizedPath(path string, source, target *Language) string {
+	ext := filepath.Ext(path)
+	for _, targetExt := range target.Extensions {
+		if strings.EqualFold(ext, targetExt) {
+			return path
+		}
+	}
+
+	newExt := target.Extension()
+	for i, sourceExt := range source.Extensions {
+		if strings.EqualFold(ext, sourceExt) && i < len(target.Extensions) {
+			newExt = target.Extensions[i]
+		}
+	}
+	return changeExtension(path, newExt)
+}
+
+// modernizeUsingLLM asks the model to upgrade code within its language family
+func modernizeUsingLLM(provider Provider, sourceCode, from, to string, guidance ...string) (string, error) {
+	prompt := fmt.Sprintf("Modernize the following %s code to %s. Keep its behaviour, public API, comments and formatting, and only change what the upgrade requires; if nothing needs to change, return the code exactly as it is:\n\n%s. Just return the code, no other text.", from, to, sourceCode)
+	if len(guidance) > 0 {
+		prompt += "\n\n" + strings.Join(guidance, "\n")
+	}
+
+	modernized, err := provider.Generate(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to modernize %s code: %w", from, err)
+	}
+	return modernized, nil
+}
@@ -0,0 +1,165 @@
+package converter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUnifiedDiff tests hunk headers, context lines and missing final newlines
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "single change",
+			old:  "a\nb\nc\n",
+			new:  "a\nB\nc\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "distant changes split into hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "missing newline",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("f", "f", tt.old, tt.new); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	created := unifiedDiff("", "new.ts", "", "x\n")
+	if created != "--- /dev/null\n+++ b/new.ts\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("unifiedDiff() for a new file = %q", created)
+	}
+}
+
+// TestModernizedPath tests that files keep their path unless the target uses another extension
+func TestModernizedPath(t *testing.T) {
+	js, _ := LookupLanguage("javascript")
+	ts, _ := LookupLanguage("typescript")
+	py, _ := LookupLanguage("python")
+
+	tests := []struct {
+		path           string
+		source, target *Language
+		want           string
+	}{
+		{"src/app.py", py, py, "src/app.py"},
+		{"src/app.js", js, ts, "src/app.ts"},
+		{"src/view.jsx", js, ts, "src/view.tsx"},
+		{"src/lib.mjs", js, ts, "src/lib.mts"},
+		{"src/types.ts", ts, ts, "src/types.ts"},
+	}
+
+	for _, tt := range tests {
+		if got := modernizedPath(tt.path, tt.source, tt.target); got != tt.want {
+			t.Errorf("modernizedPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestModernizePatch tests that only changed files in the target's family appear in the patch
+func TestModernizePatch(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"legacy.py": "print 'hello'\n",
+		"modern.py": "print('hello')\n",
+		"main.go":   "package main\n",
+	})
+
+	defer setupMockProvider(func(prompt string) (string, error) {
+		if strings.Contains(prompt, "print 'hello'") {
+			return "print('hello')", nil
+		}
+		return "print('hello')\n", nil
+	})()
+
+	var patch, log bytes.Buffer
+	c := NewConverter(root, "", "python@3.12")
+	c.SetLogOutput(&log)
+	if err := c.Modernize(&patch); err != nil {
+		t.Fatalf("Modernize() error = %v", err)
+	}
+
+	want := "--- a/legacy.py\n+++ b/legacy.py\n@@ -1 +1 @@\n-print 'hello'\n+print('hello')\n"
+	if patch.String() != want {
+		t.Errorf("Modernize() patch =\n%s\nwant\n%s", patch.String(), want)
+	}
+
+	// The sources themselves must not change without -write
+	if content, _ := os.ReadFile(filepath.Join(root, "legacy.py")); string(content) != "print 'hello'\n" {
+		t.Errorf("legacy.py was modified: %q", content)
+	}
+
+	actions := map[string]string{}
+	for _, f := range c.Report().Files {
+		actions[f.Source] = f.Action
+	}
+	if actions["legacy.py"] != actionModernized || actions["modern.py"] != actionUnchanged {
+		t.Errorf("Unexpected report actions: %v", actions)
+	}
+	if _, ok := actions["main.go"]; ok {
+		t.Errorf("Files outside the target's family should not be reported, got %v", actions)
+	}
+}
+
+// TestModernizeWriteInPlace tests that -write rewrites files and renames them to the target extension
+func TestModernizeWriteInPlace(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"src/app.js":  "const x = 1;\n",
+		"src/keep.js": "const y = 2;\n",
+		"src/keep.ts": "const y: number = 2;\n",
+	})
+
+	cleanup := setupMockGPT("const x: number = 1;", nil)
+	defer cleanup()
+
+	var patch, log bytes.Buffer
+	c := NewConverter(root, "", "typescript")
+	c.SetLogOutput(&log)
+	c.SetWriteInPlace(true)
+	if err := c.Modernize(&patch); err != nil {
+		t.Fatalf("Modernize() error = %v", err)
+	}
+
+	if patch.Len() != 0 {
+		t.Errorf("Expected no patch output with write in place, got %q", patch.String())
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "app.js")); !os.IsNotExist(err) {
+		t.Errorf("Expected src/app.js to be renamed")
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "src", "app.ts")); string(content) != "const x: number = 1;\n" {
+		t.Errorf("src/app.ts = %q", content)
+	}
+
+	// keep.js would overwrite an existing TypeScript file, so it is skipped
+	if content, _ := os.ReadFile(filepath.Join(root, "src", "keep.js")); string(content) != "const y = 2;\n" {
+		t.Errorf("src/keep.js was modified: %q", content)
+	}
+	if !strings.Contains(log.String(), "already exists") {
+		t.Errorf("Expected the skipped file to be logged, got %q", log.String())
+	}
+}
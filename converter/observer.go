@@ -0,0 +1,71 @@
+package converter
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// ErrBlocked is passed to Observer.FileFailed for files that were not sent to a model
+// because of the egress policy or the secrets they contain; the run carries on
+var ErrBlocked = errors.New("not sent to a model: blocked by the egress policy or because it contains secrets")
+
+// Observer receives progress events from a running conversion. When files are
+// converted concurrently, its methods are called from several goroutines at once.
+// Sources are slash-separated paths relative to the common root of the inputs.
+type Observer interface {
+	// FileStarted is called before an input file is converted or copied
+	FileStarted(source string)
+	// FileConverted is called with the report entry of a converted or modernized file
+	FileConverted(file FileReport)
+	// FileCopied is called with the report entry of a file copied unchanged
+	FileCopied(file FileReport)
+	// FileFailed is called when a file could not be converted
+	FileFailed(source string, err error)
+	// UsageRecorded is called after every model response with the tokens it used
+	UsageRecorded(source, model string, usage Usage)
+}
+
+// NopObserver ignores every event; embed it to implement only some of Observer's methods
+type NopObserver struct{}
+
+func (NopObserver) FileStarted(source string)                       {}
+func (NopObserver) FileConverted(file FileReport)                   {}
+func (NopObserver) FileCopied(file FileReport)                      {}
+func (NopObserver) FileFailed(source string, err error)             {}
+func (NopObserver) UsageRecorded(source, model string, usage Usage) {}
+
+// SetObserver sends progress events to o; nil stops sending them
+func (c *Converter) SetObserver(o Observer) {
+	if o == nil {
+		o = NopObserver{}
+	}
+	c.observer = o
+}
+
+// addFile adds a file's entry to the report and tells the observer what happened to it
+func (c *Converter) addFile(entry FileReport) {
+	c.mu.Lock()
+	c.report.Files = append(c.report.Files, entry)
+	c.mu.Unlock()
+
+	switch entry.Action {
+	case actionCopied:
+		c.observer.FileCopied(entry)
+	case actionBlocked:
+		c.observer.FileFailed(entry.Source, ErrBlocked)
+	case actionSkipped:
+	default:
+		c.observer.FileConverted(entry)
+	}
+}
+
+// processJob converts or copies one file, telling the observer when it starts and fails
+func (c *Converter) processJob(job fileJob) error {
+	source := filepath.ToSlash(job.relPath)
+	c.observer.FileStarted(source)
+	err := c.processFile(job)
+	if err != nil {
+		c.observer.FileFailed(source, err)
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Options configures a Converter created with New, for programs that embed the
+// converter. Zero values keep the defaults of the command-line tool, except that
+// nothing is logged without a Logger and no scaffold is generated unless asked for.
+type Options struct {
+	// Inputs are the directories and files to convert, and OutputDir receives the result
+	Inputs    []string
+	OutputDir string
+
+	// Target is the target language with an optional version, platform and modifiers,
+	// e.g. "python@3.12"; Source overrides source language detection
+	Target string
+	Source string
+
+	// Provider names the backend code is sent to (default "openai"), Fallback the
+	// providers tried in order when it fails on a file, and Policy restricts both
+	Provider string
+	Fallback []string
+	Policy   *EgressPolicy
+
+	// Params are sent with every request; Deterministic applies the deterministic preset
+	Params        ModelParams
+	Deterministic bool
+
+	// Include and Exclude are doublestar globs on slash-separated paths relative to the
+	// common root of the inputs, e.g. "src/**/*.go"; when Include is set only matching
+	// files are processed, and files matching Exclude never are
+	Include []string
+	Exclude []string
+
+	// Concurrency is the number of files converted at once (default 1)
+	Concurrency int
+
+	Layout     Layout
+	Collisions CollisionPolicy
+	Scaffold   bool
+	Validate   bool
+	CheckAPI   bool
+
+	// ReportPath is where the JSON report is written; empty writes none
+	ReportPath string
+
+	// Logger receives progress messages, and Observer receives progress events
+	Logger   Logger
+	Observer Observer
+}
+
+// Logger receives progress messages one line at a time; *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// New creates a converter from opts, validating them up front
+func New(opts Options) (*Converter, error) {
+	if len(opts.Inputs) == 0 {
+		return nil, fmt.Errorf("no input paths given")
+	}
+	if opts.OutputDir == "" {
+		return nil, fmt.Errorf("no output directory given")
+	}
+	if _, err := ResolveLanguage(ParseTargetSpec(opts.Target).Name); err != nil {
+		return nil, fmt.Errorf("target language: %w", err)
+	}
+
+	c := NewMultiConverter(opts.Inputs, opts.OutputDir, opts.Target)
+	if opts.Source != "" {
+		lang, err := ResolveLanguage(opts.Source)
+		if err != nil {
+			return nil, fmt.Errorf("source language: %w", err)
+		}
+		c.SetSourceLanguage(lang.Name)
+	}
+	// Providers declared in a policy are registered when it is loaded
+	if opts.Provider != "" {
+		if _, err := lookupProvider(opts.Provider); err != nil {
+			return nil, err
+		}
+		c.SetProvider(opts.Provider)
+	}
+	if opts.Layout != "" {
+		layout, err := ParseLayout(string(opts.Layout))
+		if err != nil {
+			return nil, err
+		}
+		c.SetLayout(layout)
+	}
+	if opts.Collisions != "" {
+		collisions, err := ParseCollisionPolicy(string(opts.Collisions))
+		if err != nil {
+			return nil, err
+		}
+		c.SetCollisionPolicy(collisions)
+	}
+	if err := c.SetFilters(opts.Include, opts.Exclude); err != nil {
+		return nil, err
+	}
+	if opts.Policy != nil {
+		c.SetEgressPolicy(opts.Policy)
+	}
+
+	c.SetFallback(opts.Fallback)
+	c.SetModelParams(opts.Params)
+	c.SetDeterministic(opts.Deterministic)
+	c.SetConcurrency(opts.Concurrency)
+	c.SetScaffold(opts.Scaffold)
+	c.SetValidate(opts.Validate)
+	c.SetCheckAPI(opts.CheckAPI)
+	c.SetReportPath(opts.ReportPath)
+	c.SetObserver(opts.Observer)
+	if opts.Logger != nil {
+		c.SetLogger(opts.Logger)
+	} else {
+		c.SetLogOutput(io.Discard)
+	}
+	return c, nil
+}
+
+// SetLogger sends progress messages to l instead of stdout
+func (c *Converter) SetLogger(l Logger) {
+	c.log = loggerWriter{l}
+}
+
+// loggerWriter passes every line written to it to a Logger
+type loggerWriter struct {
+	l Logger
+}
+
+func (w loggerWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		w.l.Printf("%s", line)
+	}
+	return len(p), nil
+}
+
+// SetFilters limits the files processed to those matching an include glob, if any are
+// given, and not matching an exclude glob. Globs match slash-separated paths relative
+// to the common root of the inputs.
+func (c *Converter) SetFilters(include, exclude []string) error {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if err := checkGlob(pattern); err != nil {
+			return fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+		}
+	}
+	c.include, c.exclude = include, exclude
+	return nil
+}
+
+// checkGlob reports a malformed glob, which doublestar only notices once it reaches
+// the malformed part while matching a path
+func checkGlob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selected reports whether the filters let a file through
+func (c *Converter) selected(relPath string) bool {
+	slashed := filepath.ToSlash(relPath)
+	for _, pattern := range c.exclude {
+		if ok, _ := doublestar.Match(pattern, slashed); ok {
+			return false
+		}
+	}
+	if len(c.include) == 0 {
+		return true
+	}
+	for _, pattern := range c.include {
+		if ok, _ := doublestar.Match(pattern, slashed); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingObserver collects the events of a run
+type recordingObserver struct {
+	NopObserver
+	mu     sync.Mutex
+	events []string
+	tokens int
+}
+
+func (o *recordingObserver) add(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) FileStarted(source string)     { o.add("started " + source) }
+func (o *recordingObserver) FileConverted(file FileReport) { o.add("converted " + file.Source) }
+func (o *recordingObserver) FileCopied(file FileReport)    { o.add("copied " + file.Source) }
+
+func (o *recordingObserver) FileFailed(source string, err error) {
+	o.add(fmt.Sprintf("failed %s: %v", source, err))
+}
+
+func (o *recordingObserver) UsageRecorded(source, model string, usage Usage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tokens += usage.TotalTokens
+}
+
+// TestNew tests converting with Options, concurrently, with filters, a logger and an observer
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"README.md":          "# Demo\n",
+		"vendor/lib/lib.go":  "package lib\n",
+		"internal/secret.go": "package internal\n",
+	}
+	for i := range 8 {
+		files[fmt.Sprintf("pkg/file%d.go", i)] = fmt.Sprintf("package pkg\n\nfunc F%d() {}\n", i)
+	}
+	writeSourceFiles(t, root, files)
+	RegisterProvider(pricedProvider{name: "test-embedded", text: "def f():\n    pass\n", model: "embedded-model"})
+	defer delete(providers, "test-embedded")
+
+	var logged strings.Builder
+	observer := &recordingObserver{}
+	out := t.TempDir()
+	c, err := New(Options{
+		Inputs:      []string{root},
+		OutputDir:   out,
+		Target:      "python",
+		Provider:    "test-embedded",
+		Exclude:     []string{"vendor/**", "internal/*"},
+		Concurrency: 4,
+		ReportPath:  filepath.Join(out, "report.json"),
+		Logger:      log.New(&logged, "converter: ", 0),
+		Observer:    observer,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	report := c.Report()
+	var sources []string
+	for _, f := range report.Files {
+		sources = append(sources, f.Source)
+	}
+	if !sort.StringsAreSorted(sources) || len(sources) != 9 {
+		t.Errorf("Expected the 9 selected files in input order, got %v", sources)
+	}
+	if usage := report.Usage["embedded-model"]; usage == nil || usage.Requests != 8 {
+		t.Errorf("Expected 8 metered requests, got %+v", usage)
+	}
+
+	events := map[string]int{}
+	for _, e := range observer.events {
+		events[strings.Fields(e)[0]]++
+	}
+	if events["started"] != 9 || events["converted"] != 8 || events["copied"] != 1 || events["failed"] != 0 {
+		t.Errorf("Unexpected events: %v", observer.events)
+	}
+	if observer.tokens != 8*1500 {
+		t.Errorf("Expected 8 usage events of 1500 tokens, got %d tokens", observer.tokens)
+	}
+	if !strings.Contains(logged.String(), "converter: Converting ") {
+		t.Errorf("Expected progress messages through the logger, got %q", logged.String())
+	}
+
+	for _, opts := range []Options{
+		{OutputDir: out, Target: "python"},
+		{Inputs: []string{root}, OutputDir: out, Target: "cobol-2000"},
+		{Inputs: []string{root}, OutputDir: out, Target: "python", Provider: "test-missing"},
+		{Inputs: []string{root}, OutputDir: out, Target: "python", Include: []string{"src/[*.go"}},
+	} {
+		if _, err := New(opts); err == nil {
+			t.Errorf("Expected New(%+v) to fail", opts)
+		}
+	}
+}
+
+// TestConcurrentFailure tests that a failing file stops the run and is reported to the observer
+func TestConcurrentFailure(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"a.go": "package a\n",
+		"b.go": "package b\n",
+		"c.go": "package c\n",
+	})
+	failing, _ := NewFakeProvider("test-failing",
+		FakeResponse{Path: "b.go", Error: "backend unavailable"},
+		FakeResponse{Response: "pass\n"},
+	)
+	RegisterProvider(failing)
+	defer delete(providers, "test-failing")
+
+	observer := &recordingObserver{}
+	c, err := New(Options{Inputs: []string{root}, OutputDir: t.TempDir(), Target: "python", Provider: "test-failing", Concurrency: 3, Observer: observer})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	err = c.Convert()
+	if err == nil || !strings.Contains(err.Error(), "backend unavailable") {
+		t.Fatalf("Expected the failure of b.go, got %v", err)
+	}
+	var failed []string
+	for _, e := range observer.events {
+		if strings.HasPrefix(e, "failed ") {
+			failed = append(failed, e)
+		}
+	}
+	if len(failed) != 1 || !strings.HasPrefix(failed[0], "failed b.go:") {
+		t.Errorf("Expected one failure event for b.go, got %v", observer.events)
+	}
+	if errors.Is(err, ErrBlocked) {
+		t.Error("Expected a provider error, not a blocked file")
+	}
+}
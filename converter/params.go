@@ -0,0 +1,45 @@
+package converter
+
+import "sort"
+
+// deterministicSeed is the seed of deterministic runs, which is also the seed of the
+// first sample, so a sampled run's first candidate matches a deterministic run
+const deterministicSeed = 1
+
+// SetModelParams sets the sampling settings sent with every request; they take
+// precedence over those of the provider's configuration and the deterministic preset
+func (c *Converter) SetModelParams(params ModelParams) {
+	c.params = params
+}
+
+// SetDeterministic asks for temperature 0 and a fixed seed, and orders the prompt's
+// configured guidance stably, so that rerunning on unchanged input produces the same
+// output where the provider allows it
+func (c *Converter) SetDeterministic(enabled bool) {
+	c.deterministic = enabled
+}
+
+// requestParams returns the parameters sent with every request of the run
+func (c *Converter) requestParams() ModelParams {
+	var params ModelParams
+	if c.deterministic {
+		temperature := float32(0)
+		seed := deterministicSeed
+		params = ModelParams{Temperature: &temperature, Seed: &seed}
+	}
+	return params.merge(c.params)
+}
+
+// stableGuidance sorts guidance and drops duplicates, so that the order in which
+// modifiers and instructions were given does not change the prompt
+func stableGuidance(guidance []string) []string {
+	sorted := append([]string(nil), guidance...)
+	sort.Strings(sorted)
+	var result []string
+	for i, g := range sorted {
+		if i == 0 || g != sorted[i-1] {
+			result = append(result, g)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package converter
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// paramsRecorder records the parameters and prompt of every request
+type paramsRecorder struct {
+	params   ModelParams
+	requests *[]ModelParams
+	prompts  *[]string
+}
+
+func (p paramsRecorder) Name() string { return "test-params" }
+
+func (p paramsRecorder) Generate(prompt string) (string, error) {
+	*p.requests = append(*p.requests, p.params)
+	*p.prompts = append(*p.prompts, prompt)
+	return "def add(a, b):\n    return a + b\n", nil
+}
+
+func (p paramsRecorder) WithParams(params ModelParams) Provider {
+	return paramsRecorder{params: params, requests: p.requests, prompts: p.prompts}
+}
+
+// TestModelParamsApply tests setting the parameters on an OpenAI-compatible request
+func TestModelParamsApply(t *testing.T) {
+	zero, half := float32(0), float32(0.5)
+	tokens, seed := 256, 7
+	base := ModelParams{Temperature: &half, MaxTokens: &tokens, System: "Be terse."}
+	params := base.merge(ModelParams{Temperature: &zero, TopP: &half, Seed: &seed})
+
+	req := openai.ChatCompletionRequest{Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "prompt"}}}
+	params.apply(&req)
+	if req.Temperature <= 0 || req.Temperature > 0.001 {
+		t.Errorf("Expected a zero temperature to be sent as the smallest positive one, got %g", req.Temperature)
+	}
+	if req.TopP != 0.5 || req.MaxTokens != 256 || req.Seed == nil || *req.Seed != 7 {
+		t.Errorf("Unexpected request parameters: top_p %g, max_tokens %d, seed %v", req.TopP, req.MaxTokens, req.Seed)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != openai.ChatMessageRoleSystem || req.Messages[0].Content != "Be terse." {
+		t.Errorf("Expected the system prompt ahead of the prompt, got %+v", req.Messages)
+	}
+	if recorded := params.record(); len(recorded) != 5 || recorded["max_tokens"] != 256 {
+		t.Errorf("Unexpected recorded parameters: %v", recorded)
+	}
+	if (ModelParams{}).record() != nil {
+		t.Error("Expected no recorded parameters when none are set")
+	}
+}
+
+// TestDeterministic tests the deterministic preset and its stable prompt ordering
+func TestDeterministic(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"math.go": "package math\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+	})
+
+	var requests []ModelParams
+	var prompts []string
+	RegisterProvider(paramsRecorder{requests: &requests, prompts: &prompts})
+	defer delete(providers, "test-params")
+
+	convert := func(target string, guidance ...string) *Converter {
+		c := NewConverter(root, t.TempDir(), target)
+		c.SetScaffold(false)
+		c.SetProvider("test-params")
+		c.SetDeterministic(true)
+		seed := 99
+		c.SetModelParams(ModelParams{Seed: &seed})
+		for _, g := range guidance {
+			c.AddGuidance(g)
+		}
+		c.SetLogOutput(io.Discard)
+		if err := c.Convert(); err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		return c
+	}
+
+	c := convert("python@3.12 typed async", "Use dataclasses.", "Prefer pathlib.")
+	convert("python@3.12 async typed", "Prefer pathlib.", "Use dataclasses.", "Use dataclasses.")
+
+	if len(requests) != 2 || requests[0].Temperature == nil || *requests[0].Temperature != 0 || *requests[0].Seed != 99 {
+		t.Fatalf("Expected temperature 0 and the explicit seed, got %+v", requests)
+	}
+	if params := c.Report().Params; params == nil || *params.Seed != 99 {
+		t.Errorf("Expected the parameters in the report, got %+v", params)
+	}
+	if prompts[0] != prompts[1] {
+		t.Errorf("Expected the same prompt regardless of the order of modifiers and guidance:\n%s\n---\n%s", prompts[0], prompts[1])
+	}
+	if strings.Count(prompts[1], "Use dataclasses.") != 1 {
+		t.Errorf("Expected repeated guidance once, got %q", prompts[1])
+	}
+}
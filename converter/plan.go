@@ -0,0 +1,264 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CollisionPolicy decides what happens when two inputs map to the same output path
+type CollisionPolicy string
+
+const (
+	// CollisionError aborts the run before anything is written
+	CollisionError CollisionPolicy = "error"
+	// CollisionRename disambiguates colliding outputs by appending the source extension
+	CollisionRename CollisionPolicy = "rename"
+)
+
+// ParseCollisionPolicy validates a collision policy given on the command line
+func ParseCollisionPolicy(name string) (CollisionPolicy, error) {
+	switch policy := CollisionPolicy(strings.ToLower(name)); policy {
+	case CollisionError, CollisionRename:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown collision policy %q (expected error or rename)", name)
+}
+
+// SetCollisionPolicy selects how output path collisions are handled
+func (c *Converter) SetCollisionPolicy(policy CollisionPolicy) {
+	c.collisions = policy
+}
+
+// fileJob is a single input file and the output path planned for it
+type fileJob struct {
+	inputPath  string
+	relPath    string
+	outputPath string
+	sourceLang string
+	detection  Detection
+	convert    bool
+
+	// test marks source files holding tests; subjectOutput is where the code they exercise is written
+	test          bool
+	subjectOutput string
+	// generated marks characterisation tests written for the run rather than found in the inputs
+	generated bool
+}
+
+// plan resolves every input to a file job, mirroring paths relative to the common root of all inputs
+func (c *Converter) plan() ([]fileJob, error) {
+	jobs, err := c.collectJobs()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.resolveCollisions(jobs); err != nil {
+		return nil, err
+	}
+	linkTestSubjects(jobs)
+	return jobs, nil
+}
+
+// collectJobs walks every input and creates a job per file without checking output collisions
+func (c *Converter) collectJobs() ([]fileJob, error) {
+	if _, err := ResolveLanguage(c.targetLang); err != nil {
+		return nil, fmt.Errorf("target language: %w", err)
+	}
+
+	inputs := make([]string, 0, len(c.inputs))
+	dirs := make([]string, 0, len(c.inputs))
+	for _, input := range c.inputs {
+		abs, err := filepath.Abs(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve input path %s: %w", input, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access input path %s: %w", input, err)
+		}
+
+		inputs = append(inputs, abs)
+		if info.IsDir() {
+			dirs = append(dirs, abs)
+		} else {
+			dirs = append(dirs, filepath.Dir(abs))
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no input paths given")
+	}
+
+	c.inputDir = commonRoot(dirs)
+
+	var jobs []fileJob
+	seen := map[string]bool{}
+	for _, input := range inputs {
+		rel, err := filepath.Rel(c.inputDir, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute relative path for %s: %w", input, err)
+		}
+		if rel == "." {
+			rel = ""
+		}
+
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access input path %s: %w", input, err)
+		}
+		if info.IsDir() {
+			if err := c.collectDirectory(input, rel, &jobs, seen); err != nil {
+				return nil, err
+			}
+		} else if !seen[input] && c.selected(rel) {
+			seen[input] = true
+			jobs = append(jobs, c.newJob(input, rel))
+		}
+	}
+
+	return jobs, nil
+}
+
+// collectDirectory recursively adds a job for every file in a directory
+func (c *Converter) collectDirectory(inputPath, relDir string, jobs *[]fileJob, seen map[string]bool) error {
+	entries, err := os.ReadDir(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", inputPath, err)
+	}
+
+	for _, entry := range entries {
+		inPath := filepath.Join(inputPath, entry.Name())
+		relPath := filepath.Join(relDir, entry.Name())
+
+		if entry.IsDir() {
+			// Skip common directories to ignore
+			if shouldIgnoreDir(entry.Name()) {
+				c.logf("Skipping directory: %s\n", inPath)
+				continue
+			}
+
+			if err := c.collectDirectory(inPath, relPath, jobs, seen); err != nil {
+				return err
+			}
+		} else if !seen[inPath] && c.selected(relPath) {
+			seen[inPath] = true
+			*jobs = append(*jobs, c.newJob(inPath, relPath))
+		}
+	}
+
+	return nil
+}
+
+// newJob plans the output path for a single input file
+func (c *Converter) newJob(inputPath, relPath string) fileJob {
+	det := c.detectFile(inputPath)
+	shouldProcess := det.Language != ""
+	job := fileJob{
+		inputPath:  inputPath,
+		relPath:    relPath,
+		outputPath: c.outputPathFor(relPath, shouldProcess),
+		sourceLang: det.Language,
+		detection:  det,
+		convert:    shouldProcess,
+		test:       shouldProcess && isTestFile(relPath, det.Language),
+	}
+	if job.test {
+		job.outputPath = c.testOutputPath(relPath)
+	}
+	return job
+}
+
+// resolveCollisions reports or disambiguates jobs that would write to the same output path
+func (c *Converter) resolveCollisions(jobs []fileJob) error {
+	groups := collidingJobs(jobs)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	if c.collisions != CollisionRename {
+		var lines []string
+		for _, group := range groups {
+			var sources []string
+			for _, i := range group {
+				sources = append(sources, jobs[i].inputPath)
+			}
+			lines = append(lines, fmt.Sprintf("  %s <- %s", jobs[group[0]].outputPath, strings.Join(sources, ", ")))
+		}
+		return fmt.Errorf("output path collisions detected (use -on-collision rename to disambiguate):\n%s", strings.Join(lines, "\n"))
+	}
+
+	// Colliding outputs keep their name with the source extension appended, e.g. util_js.py and util_ts.py
+	for _, group := range groups {
+		for _, i := range group {
+			srcExt := strings.TrimPrefix(filepath.Ext(jobs[i].inputPath), ".")
+			if srcExt == "" {
+				continue
+			}
+			ext := filepath.Ext(jobs[i].outputPath)
+			jobs[i].outputPath = strings.TrimSuffix(jobs[i].outputPath, ext) + "_" + srcExt + ext
+		}
+	}
+
+	// Anything still colliding gets a numeric suffix in input order
+	for _, group := range collidingJobs(jobs) {
+		for n, i := range group[1:] {
+			ext := filepath.Ext(jobs[i].outputPath)
+			jobs[i].outputPath = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(jobs[i].outputPath, ext), n+2, ext)
+		}
+	}
+
+	for _, group := range groups {
+		for _, i := range group {
+			c.logf("Renamed colliding output for %s to %s\n", jobs[i].inputPath, jobs[i].outputPath)
+		}
+	}
+	return nil
+}
+
+// collidingJobs groups job indexes by output path, ignoring case so that
+// results stay valid on case-insensitive filesystems
+func collidingJobs(jobs []fileJob) [][]int {
+	byPath := map[string][]int{}
+	var order []string
+	for i, job := range jobs {
+		key := strings.ToLower(job.outputPath)
+		if _, ok := byPath[key]; !ok {
+			order = append(order, key)
+		}
+		byPath[key] = append(byPath[key], i)
+	}
+
+	var groups [][]int
+	for _, key := range order {
+		if len(byPath[key]) > 1 {
+			groups = append(groups, byPath[key])
+		}
+	}
+	return groups
+}
+
+// commonRoot returns the deepest directory that contains every given directory
+func commonRoot(dirs []string) string {
+	sorted := append([]string(nil), dirs...)
+	sort.Strings(sorted)
+
+	root := filepath.Clean(sorted[0])
+	for _, dir := range sorted[1:] {
+		dir = filepath.Clean(dir)
+		for !withinDir(root, dir) {
+			parent := filepath.Dir(root)
+			if parent == root {
+				break
+			}
+			root = parent
+		}
+	}
+	return root
+}
+
+// withinDir reports whether path is dir itself or lies below it
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
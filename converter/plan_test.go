@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSourceFiles creates files with the given contents under root
+func writeSourceFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for file, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", file, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", file, err)
+		}
+	}
+}
+
+// TestCommonRoot tests the common root computation for multiple inputs
+func TestCommonRoot(t *testing.T) {
+	root := filepath.FromSlash("/src/project")
+	tests := []struct {
+		dirs []string
+		want string
+	}{
+		{[]string{root}, root},
+		{[]string{filepath.Join(root, "a"), filepath.Join(root, "b")}, root},
+		{[]string{filepath.Join(root, "a", "x"), filepath.Join(root, "a")}, filepath.Join(root, "a")},
+		{[]string{filepath.Join(root, "ab"), filepath.Join(root, "a")}, root},
+	}
+
+	for _, tt := range tests {
+		if got := commonRoot(tt.dirs); got != tt.want {
+			t.Errorf("commonRoot(%v) = %q, want %q", tt.dirs, got, tt.want)
+		}
+	}
+}
+
+// TestMultiFileInputsPreserveRelativePaths tests that files with the same base name do not overwrite each other
+func TestMultiFileInputsPreserveRelativePaths(t *testing.T) {
+	tempInput := t.TempDir()
+	tempOutput := t.TempDir()
+	writeSourceFiles(t, tempInput, map[string]string{"a/util.go": "// a/util.go", "b/util.go": "// b/util.go"})
+
+	cleanup := setupMockGPT("# Converted code", nil)
+	defer cleanup()
+
+	c := NewMultiConverter([]string{
+		filepath.Join(tempInput, "a", "util.go"),
+		filepath.Join(tempInput, "b", "util.go"),
+	}, tempOutput, "python")
+	c.SetScaffold(false)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	for _, path := range []string{"a/util.py", "b/util.py"} {
+		if _, err := os.Stat(filepath.Join(tempOutput, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+// TestOutputCollisions tests that colliding outputs are reported or renamed deterministically
+func TestOutputCollisions(t *testing.T) {
+	tempInput := t.TempDir()
+	writeSourceFiles(t, tempInput, map[string]string{"a.js": "// a.js", "a.ts": "// a.ts"})
+
+	cleanup := setupMockGPT("# Converted code", nil)
+	defer cleanup()
+
+	t.Run("error", func(t *testing.T) {
+		tempOutput := t.TempDir()
+		err := NewConverter(tempInput, tempOutput, "python").Convert()
+		if err == nil || !strings.Contains(err.Error(), "collision") {
+			t.Fatalf("Convert() error = %v, want collision error", err)
+		}
+		if entries, _ := os.ReadDir(tempOutput); len(entries) != 0 {
+			t.Errorf("Expected nothing to be written, found %d entries", len(entries))
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		tempOutput := t.TempDir()
+		c := NewConverter(tempInput, tempOutput, "python")
+		c.SetCollisionPolicy(CollisionRename)
+		c.SetScaffold(false)
+		if err := c.Convert(); err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		for _, name := range []string{"a_js.py", "a_ts.py"} {
+			if _, err := os.Stat(filepath.Join(tempOutput, name)); err != nil {
+				t.Errorf("Expected %s to exist: %v", name, err)
+			}
+		}
+	})
+}
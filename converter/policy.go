@@ -0,0 +1,272 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// Violation handling for files the selected provider may not receive
+const (
+	ViolationSkip  = "skip"
+	ViolationRoute = "route"
+)
+
+// EgressRule restricts the providers that matching files may be sent to. A rule
+// matches a file when every selector it sets matches: one of its paths or globs,
+// one of its languages and one of its markers, such as a license header. An
+// empty provider list means matching files may not be sent anywhere.
+type EgressRule struct {
+	Paths     []string `json:"paths,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	Markers   []string `json:"markers,omitempty"`
+	Providers []string `json:"providers"`
+}
+
+// EgressPolicy decides which providers each file may be sent to. Files matching
+// several rules may only go to providers that all of them allow; files matching
+// none may go to the Default providers, or anywhere when Default is unset.
+type EgressPolicy struct {
+	Providers   map[string]ProviderConfig `json:"providers,omitempty"`
+	Default     []string                  `json:"default,omitempty"`
+	Rules       []EgressRule              `json:"rules"`
+	OnViolation string                    `json:"on_violation,omitempty"`
+}
+
+// EgressViolation records a file that the selected provider was not allowed to receive
+type EgressViolation struct {
+	Provider string   `json:"provider"`
+	Allowed  []string `json:"allowed"`
+	Rules    []int    `json:"rules,omitempty"`
+	Action   string   `json:"action"`
+}
+
+func (v EgressViolation) String() string {
+	source := "the default providers"
+	if len(v.Rules) > 0 {
+		source = fmt.Sprintf("rules %v", v.Rules)
+	}
+	allowed := "no provider"
+	if len(v.Allowed) > 0 {
+		allowed = strings.Join(v.Allowed, ", ")
+	}
+	return fmt.Sprintf("%s is not allowed by %s (allowed: %s)", v.Provider, source, allowed)
+}
+
+// LoadEgressPolicy reads a policy file and registers the providers it declares
+func LoadEgressPolicy(policyPath string) (*EgressPolicy, error) {
+	data, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", policyPath, err)
+	}
+	var policy EgressPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", policyPath, err)
+	}
+
+	for name, config := range policy.Providers {
+		p, err := NewCompatibleProvider(name, config)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", policyPath, err)
+		}
+		RegisterProvider(p)
+	}
+
+	switch policy.OnViolation {
+	case "":
+		policy.OnViolation = ViolationSkip
+	case ViolationSkip, ViolationRoute:
+	default:
+		return nil, fmt.Errorf("policy %s: on_violation must be %s or %s, not %q", policyPath, ViolationSkip, ViolationRoute, policy.OnViolation)
+	}
+
+	for _, name := range policy.Default {
+		if _, err := lookupProvider(name); err != nil {
+			return nil, fmt.Errorf("policy %s: default: %w", policyPath, err)
+		}
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Paths) == 0 && len(rule.Languages) == 0 && len(rule.Markers) == 0 {
+			return nil, fmt.Errorf("policy %s: rule %d has no paths, languages or markers", policyPath, i+1)
+		}
+		for _, pattern := range rule.Paths {
+			if _, err := doublestar.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("policy %s: rule %d: invalid pattern %q: %w", policyPath, i+1, pattern, err)
+			}
+		}
+		for j, name := range rule.Languages {
+			lang, err := ResolveLanguage(name)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: rule %d: %w", policyPath, i+1, err)
+			}
+			policy.Rules[i].Languages[j] = lang.Name
+		}
+		for _, name := range rule.Providers {
+			if _, err := lookupProvider(name); err != nil {
+				return nil, fmt.Errorf("policy %s: rule %d: %w", policyPath, i+1, err)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// SetEgressPolicy restricts which providers each file may be sent to
+func (c *Converter) SetEgressPolicy(policy *EgressPolicy) {
+	c.policy = policy
+}
+
+// allowed returns the providers a file may be sent to, or nil when any provider
+// may receive it, along with the 1-based numbers of the rules that matched
+func (p *EgressPolicy) allowed(relPath, lang, content string) ([]string, []int) {
+	var allowed []string
+	var matched []int
+	for i, rule := range p.Rules {
+		if !rule.matches(relPath, lang, content) {
+			continue
+		}
+		if matched == nil {
+			allowed = append([]string{}, rule.Providers...)
+		} else {
+			allowed = intersect(allowed, rule.Providers)
+		}
+		matched = append(matched, i+1)
+	}
+	if matched == nil {
+		return p.Default, nil
+	}
+	return allowed, matched
+}
+
+// matches reports whether every selector the rule sets matches the file
+func (r EgressRule) matches(relPath, lang, content string) bool {
+	if len(r.Paths) > 0 && !matchesAnyPath(r.Paths, relPath) {
+		return false
+	}
+	if len(r.Languages) > 0 && !containsString(r.Languages, lang) {
+		return false
+	}
+	if len(r.Markers) > 0 {
+		found := false
+		for _, marker := range r.Markers {
+			if strings.Contains(content, marker) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAnyPath matches a slash-separated relative path against globs and plain
+// paths; a plain path also matches everything below it
+func matchesAnyPath(patterns []string, relPath string) bool {
+	if relPath == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(path.Clean(pattern), "/")
+		if ok, _ := doublestar.Match(pattern, relPath); ok {
+			return true
+		}
+		if relPath == pattern || strings.HasPrefix(relPath, pattern+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// intersect returns the values of a that are also in b, in a's order
+func intersect(a, b []string) []string {
+	result := []string{}
+	for _, v := range a {
+		if containsString(b, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// providerFor returns the provider a file about to be sent to the model may go to,
+// or nil when it must not be sent, along with any egress policy violation for the
+// report. Providers that match on the file are told which one it is, token usage
+// is added to the report, and requests are recorded when a transcript is set.
+func (c *Converter) providerFor(relPath, lang, content string) (Provider, *EgressViolation, error) {
+	return c.providerNamed(c.provider, relPath, lang, content)
+}
+
+// providerNamed is providerFor for a provider other than the selected one, such as the reviewer
+func (c *Converter) providerNamed(name, relPath, lang, content string) (Provider, *EgressViolation, error) {
+	p, violation, err := c.permittedProvider(name, relPath, lang, content)
+	if b, ok := p.(fileBinder); ok {
+		target := c.targetLang
+		if l, ok := languages.lookup(target); ok {
+			target = l.Name
+		}
+		p = b.ForFile(relPath, lang, target)
+	}
+	if params := c.requestParams(); p != nil && params != (ModelParams{}) {
+		p = withParams(p, params)
+	}
+	if p != nil {
+		p = meteredProvider{Provider: p, c: c, file: relPath}
+	}
+	if p != nil && c.transcript != nil {
+		p = transcriptProvider{Provider: p, transcript: c.transcript, log: c.log}
+	}
+	return p, violation, err
+}
+
+// permittedProvider applies the egress policy to the named provider, routing the
+// file to a permitted one when the policy says so. An empty relPath stands for stdin.
+func (c *Converter) permittedProvider(name, relPath, lang, content string) (Provider, *EgressViolation, error) {
+	if name == "" {
+		name = defaultProviderName
+	}
+	selected, err := lookupProvider(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.policy == nil {
+		return selected, nil, nil
+	}
+
+	allowed, rules := c.policy.allowed(filepath.ToSlash(relPath), lang, content)
+	if allowed == nil || containsString(allowed, name) {
+		return selected, nil, nil
+	}
+
+	violation := &EgressViolation{Provider: name, Allowed: allowed, Rules: rules, Action: actionBlocked}
+	file := relPath
+	if file == "" {
+		file = "stdin"
+	}
+	if c.policy.OnViolation == ViolationRoute && len(allowed) > 0 {
+		routed, err := lookupProvider(allowed[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		violation.Action = "routed to " + routed.Name()
+		c.logf("Egress policy: %s for %s; sending it to %s instead\n", violation, file, routed.Name())
+		return routed, violation, nil
+	}
+	c.logf("Egress policy: %s for %s; not sending it\n", violation, file)
+	return nil, violation, nil
+}
@@ -0,0 +1,143 @@
+package converter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingProvider answers every prompt and remembers which ones it received
+type recordingProvider struct {
+	name    string
+	prompts *[]string
+}
+
+func (p recordingProvider) Name() string { return p.name }
+
+func (p recordingProvider) Generate(prompt string) (string, error) {
+	*p.prompts = append(*p.prompts, prompt)
+	return "# by " + p.name + "\n", nil
+}
+
+// TestLoadEgressPolicy tests parsing and validating policy files
+func TestLoadEgressPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeSourceFiles(t, dir, map[string]string{
+		"policy.json": `{
+			"providers": {"test-local": {"base_url": "http://localhost:11434/v1", "model": "llama3"}},
+			"rules": [
+				{"paths": ["internal"], "providers": ["test-local"]},
+				{"languages": ["golang"], "markers": ["Proprietary"], "providers": []}
+			],
+			"on_violation": "route"
+		}`,
+		"unknown.json": `{"rules": [{"paths": ["x"], "providers": ["nowhere"]}]}`,
+		"empty.json":   `{"rules": [{"providers": ["openai"]}]}`,
+	})
+	defer delete(providers, "test-local")
+
+	policy, err := LoadEgressPolicy(filepath.Join(dir, "policy.json"))
+	if err != nil {
+		t.Fatalf("LoadEgressPolicy() error = %v", err)
+	}
+	if _, err := lookupProvider("test-local"); err != nil {
+		t.Errorf("Expected the declared provider to be registered: %v", err)
+	}
+
+	tests := []struct {
+		path, lang, content string
+		want                string
+		rules               []int
+	}{
+		{"internal/db/conn.go", "Go", "", "test-local", []int{1}},
+		{"internal", "Go", "", "test-local", []int{1}},
+		{"internals.go", "Go", "", "<any>", nil},
+		{"cmd/main.go", "Go", "// Proprietary and confidential", "", []int{2}},
+		{"internal/secret.go", "Go", "// Proprietary", "", []int{1, 2}},
+		{"cmd/main.py", "Python", "# Proprietary", "<any>", nil},
+	}
+	for _, tt := range tests {
+		allowed, rules := policy.allowed(tt.path, tt.lang, tt.content)
+		got := strings.Join(allowed, ",")
+		if allowed == nil {
+			got = "<any>"
+		}
+		if got != tt.want || len(rules) != len(tt.rules) {
+			t.Errorf("allowed(%s) = %q %v, want %q %v", tt.path, got, rules, tt.want, tt.rules)
+		}
+	}
+
+	for _, name := range []string{"unknown.json", "empty.json"} {
+		if _, err := LoadEgressPolicy(filepath.Join(dir, name)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+// TestConvertEnforcesEgressPolicy tests skipping and routing files the selected provider may not receive
+func TestConvertEnforcesEgressPolicy(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"public/api.go":     "package api\n",
+		"internal/store.go": "package store\n",
+	})
+
+	var remote, local []string
+	RegisterProvider(recordingProvider{"test-remote", &remote})
+	RegisterProvider(recordingProvider{"test-local", &local})
+	defer delete(providers, "test-remote")
+	defer delete(providers, "test-local")
+
+	policy := &EgressPolicy{
+		Rules:       []EgressRule{{Paths: []string{"internal/**"}, Providers: []string{"test-local"}}},
+		OnViolation: ViolationSkip,
+	}
+
+	out := t.TempDir()
+	c := NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetProvider("test-remote")
+	c.SetEgressPolicy(policy)
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(remote) != 1 || len(local) != 0 || strings.Contains(remote[0], "package store") {
+		t.Fatalf("Expected only the public file to be sent, got remote=%d local=%d", len(remote), len(local))
+	}
+	if _, err := os.Stat(filepath.Join(out, "internal", "store.py")); !os.IsNotExist(err) {
+		t.Errorf("Expected no output for the skipped file")
+	}
+	for _, f := range c.Report().Files {
+		if f.Source == "internal/store.go" && (f.Action != actionBlocked || f.Egress == nil || f.Egress.Provider != "test-remote") {
+			t.Errorf("Unexpected report entry for the skipped file: %+v", f)
+		}
+	}
+
+	// Routing sends the file to the permitted provider instead
+	remote, local = nil, nil
+	policy.OnViolation = ViolationRoute
+	out = t.TempDir()
+	c = NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetProvider("test-remote")
+	c.SetEgressPolicy(policy)
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(remote) != 1 || len(local) != 1 {
+		t.Fatalf("Expected one file per provider, got remote=%d local=%d", len(remote), len(local))
+	}
+	converted, err := os.ReadFile(filepath.Join(out, "internal", "store.py"))
+	if err != nil || string(converted) != "# by test-local\n" {
+		t.Errorf("Expected the routed file to be converted by test-local, got %q (%v)", converted, err)
+	}
+	for _, f := range c.Report().Files {
+		if f.Source == "internal/store.go" && f.Provider != "test-local" {
+			t.Errorf("Provider = %s, want test-local", f.Provider)
+		}
+	}
+}
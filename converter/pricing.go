@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"sort"
+	"strings"
+)
+
+// Price is what a model charges in US dollars per million prompt and completion tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// defaultPrices holds the list prices of the models used most often; models are
+// matched by their longest listed prefix, so dated snapshots share a price
+var defaultPrices = map[string]Price{
+	"gpt-4o":       {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":  {Input: 0.15, Output: 0.60},
+	"gpt-4.1":      {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini": {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano": {Input: 0.10, Output: 0.40},
+}
+
+// cost returns what the usage costs at this price
+func (p Price) cost(u Usage) float64 {
+	return (float64(u.PromptTokens)*p.Input + float64(u.CompletionTokens)*p.Output) / 1e6
+}
+
+// priceFor returns the price of the longest model prefix listed in prices
+func priceFor(prices map[string]Price, model string) (Price, bool) {
+	best, found := "", false
+	for prefix := range prices {
+		if strings.HasPrefix(model, prefix) && (!found || len(prefix) > len(best)) {
+			best, found = prefix, true
+		}
+	}
+	return prices[best], found
+}
+
+// mergePrices returns base with the prices in extra added or overridden
+func mergePrices(base, extra map[string]Price) map[string]Price {
+	merged := make(map[string]Price, len(base)+len(extra))
+	for model, p := range base {
+		merged[model] = p
+	}
+	for model, p := range extra {
+		merged[model] = p
+	}
+	return merged
+}
+
+// estimateCost totals the tokens and cost of per-model usage, listing the models
+// that used tokens but have no price
+func estimateCost(prices map[string]Price, usage map[string]*ModelUsage) (Usage, float64, []string) {
+	models := make([]string, 0, len(usage))
+	for model := range usage {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	var total Usage
+	var cost float64
+	var unpriced []string
+	for _, model := range models {
+		u := usage[model].Usage
+		total = addUsage(total, u)
+		if p, ok := priceFor(prices, model); ok {
+			cost += p.cost(u)
+		} else if u.TotalTokens > 0 {
+			unpriced = append(unpriced, model)
+		}
+	}
+	return total, cost, unpriced
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPriceFor tests matching models to prices by their longest prefix
+func TestPriceFor(t *testing.T) {
+	tests := []struct {
+		model string
+		want  float64
+		found bool
+	}{
+		{"gpt-4o-2024-08-06", 2.50, true},
+		{"gpt-4o-mini-2024-07-18", 0.15, true},
+		{"gpt-4.1-mini", 0.40, true},
+		{"llama3", 0, false},
+	}
+	for _, tt := range tests {
+		p, found := priceFor(defaultPrices, tt.model)
+		if found != tt.found || p.Input != tt.want {
+			t.Errorf("priceFor(%s) = %v, %v; want %v, %v", tt.model, p, found, tt.want, tt.found)
+		}
+	}
+}
+
+// TestEstimateCost tests totalling tokens and cost across models
+func TestEstimateCost(t *testing.T) {
+	usage := map[string]*ModelUsage{
+		"gpt-4o":  {Requests: 2, Usage: Usage{PromptTokens: 1000000, CompletionTokens: 100000, TotalTokens: 1100000}},
+		"llama3":  {Requests: 1, Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		"unknown": {Requests: 1},
+	}
+	total, cost, unpriced := estimateCost(defaultPrices, usage)
+	if total.TotalTokens != 1100015 || math.Abs(cost-3.5) > 1e-9 || len(unpriced) != 1 || unpriced[0] != "llama3" {
+		t.Errorf("estimateCost() = %+v, %f, %v", total, cost, unpriced)
+	}
+}
@@ -0,0 +1,346 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// defaultProviderName is the provider used unless another one is selected
+const defaultProviderName = "openai"
+
+// Provider is a model backend that code can be sent to
+type Provider interface {
+	Name() string
+	Generate(prompt string) (string, error)
+}
+
+// Completion is a model response together with what is needed to audit it
+type Completion struct {
+	Text   string
+	Model  string
+	Params map[string]any
+	Usage  Usage
+}
+
+// Usage counts the tokens a request consumed
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Completer is implemented by providers that can report the model and usage of a response
+type Completer interface {
+	Complete(prompt string) (Completion, error)
+}
+
+// complete sends a prompt to a provider, including the model and usage when the provider reports them
+func complete(p Provider, prompt string) (Completion, error) {
+	if c, ok := p.(Completer); ok {
+		return c.Complete(prompt)
+	}
+	text, err := p.Generate(prompt)
+	return Completion{Text: text}, err
+}
+
+// ModelParams are the sampling settings sent with a request; unset fields keep the model's defaults
+type ModelParams struct {
+	Temperature *float32 `json:"temperature,omitempty"`
+	TopP        *float32 `json:"top_p,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Seed        *int     `json:"seed,omitempty"`
+
+	// System is sent as a system message ahead of every prompt
+	System string `json:"system,omitempty"`
+}
+
+// apply sets the parameters on an OpenAI-compatible request
+func (p ModelParams) apply(req *openai.ChatCompletionRequest) {
+	// The client omits zero temperatures and top_p values, so the smallest positive
+	// value stands in for them
+	if p.Temperature != nil {
+		req.Temperature = max(*p.Temperature, math.SmallestNonzeroFloat32)
+	}
+	if p.TopP != nil {
+		req.TopP = max(*p.TopP, math.SmallestNonzeroFloat32)
+	}
+	if p.MaxTokens != nil {
+		req.MaxTokens = *p.MaxTokens
+	}
+	req.Seed = p.Seed
+	if p.System != "" {
+		system := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: p.System}
+		req.Messages = append([]openai.ChatCompletionMessage{system}, req.Messages...)
+	}
+}
+
+// record returns the parameters that were set, for Completion.Params
+func (p ModelParams) record() map[string]any {
+	params := map[string]any{}
+	if p.Temperature != nil {
+		params["temperature"] = *p.Temperature
+	}
+	if p.TopP != nil {
+		params["top_p"] = *p.TopP
+	}
+	if p.MaxTokens != nil {
+		params["max_tokens"] = *p.MaxTokens
+	}
+	if p.Seed != nil {
+		params["seed"] = *p.Seed
+	}
+	if p.System != "" {
+		params["system"] = p.System
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+// merge returns p with the fields that over sets replaced
+func (p ModelParams) merge(over ModelParams) ModelParams {
+	if over.Temperature != nil {
+		p.Temperature = over.Temperature
+	}
+	if over.TopP != nil {
+		p.TopP = over.TopP
+	}
+	if over.MaxTokens != nil {
+		p.MaxTokens = over.MaxTokens
+	}
+	if over.Seed != nil {
+		p.Seed = over.Seed
+	}
+	if over.System != "" {
+		p.System = over.System
+	}
+	return p
+}
+
+// paramsProvider is implemented by providers whose requests can carry model parameters
+type paramsProvider interface {
+	WithParams(params ModelParams) Provider
+}
+
+// withParams returns p configured to send params, or p itself when it has no parameters to set
+func withParams(p Provider, params ModelParams) Provider {
+	if pp, ok := p.(paramsProvider); ok {
+		return pp.WithParams(params)
+	}
+	return p
+}
+
+// usageOf converts the usage reported by an OpenAI-compatible API
+func usageOf(u openai.Usage) Usage {
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens}
+}
+
+// ModelUsage totals the requests sent to one model and the tokens they used
+type ModelUsage struct {
+	Requests int `json:"requests"`
+	Usage
+}
+
+// meteredProvider adds the usage of every response about a file to the converter's report
+type meteredProvider struct {
+	Provider
+	c    *Converter
+	file string
+}
+
+func (p meteredProvider) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p meteredProvider) WithParams(params ModelParams) Provider {
+	return meteredProvider{Provider: withParams(p.Provider, params), c: p.c, file: p.file}
+}
+
+func (p meteredProvider) Complete(prompt string) (Completion, error) {
+	completion, err := complete(p.Provider, prompt)
+	p.c.recordUsage(p.file, completion)
+	return completion, err
+}
+
+// recordUsage adds a response's token usage to the report totals and to the file's own
+func (c *Converter) recordUsage(file string, completion Completion) {
+	c.mu.Lock()
+	c.report.Usage = addModelUsage(c.report.Usage, completion)
+	if c.fileUsage == nil {
+		c.fileUsage = map[string]map[string]*ModelUsage{}
+	}
+	c.fileUsage[file] = addModelUsage(c.fileUsage[file], completion)
+	c.mu.Unlock()
+
+	model := completion.Model
+	if model == "" {
+		model = "unknown"
+	}
+	c.observer.UsageRecorded(filepath.ToSlash(file), model, completion.Usage)
+}
+
+// usageFor returns the tokens spent on a file so far, by model
+func (c *Converter) usageFor(file string) map[string]*ModelUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fileUsage[file]
+}
+
+// addModelUsage adds a response to usage totals keyed by model, creating the map if needed
+func addModelUsage(usage map[string]*ModelUsage, completion Completion) map[string]*ModelUsage {
+	model := completion.Model
+	if model == "" {
+		model = "unknown"
+	}
+	if usage == nil {
+		usage = map[string]*ModelUsage{}
+	}
+	u := usage[model]
+	if u == nil {
+		u = &ModelUsage{}
+		usage[model] = u
+	}
+	u.Requests++
+	u.Usage = addUsage(u.Usage, completion.Usage)
+	return usage
+}
+
+// addUsage returns the sum of two token counts
+func addUsage(a, b Usage) Usage {
+	return Usage{
+		PromptTokens:     a.PromptTokens + b.PromptTokens,
+		CompletionTokens: a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:      a.TotalTokens + b.TotalTokens,
+	}
+}
+
+// ProviderConfig describes an OpenAI-compatible backend, such as a model served
+// locally by Ollama or vLLM
+type ProviderConfig struct {
+	BaseURL   string `json:"base_url"`
+	Model     string `json:"model"`
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+
+	// Params are sent with every request to the backend unless a run overrides them
+	Params ModelParams `json:"params,omitempty"`
+}
+
+// openAIProvider sends prompts to OpenAI's OpenAIModel
+type openAIProvider struct {
+	params ModelParams
+}
+
+func (openAIProvider) Name() string { return defaultProviderName }
+
+func (p openAIProvider) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p openAIProvider) Complete(prompt string) (Completion, error) {
+	return openAICompletion(prompt, p.params)
+}
+
+func (p openAIProvider) WithParams(params ModelParams) Provider {
+	return openAIProvider{params: params}
+}
+
+// compatibleProvider sends prompts to an OpenAI-compatible chat completions endpoint
+type compatibleProvider struct {
+	name   string
+	config ProviderConfig
+	params ModelParams
+}
+
+func (p compatibleProvider) Name() string { return p.name }
+
+func (p compatibleProvider) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p compatibleProvider) Complete(prompt string) (Completion, error) {
+	config := openai.DefaultConfig(os.Getenv(p.config.APIKeyEnv))
+	config.BaseURL = p.config.BaseURL
+	client := openai.NewClientWithConfig(config)
+	req := openai.ChatCompletionRequest{
+		Model: p.config.Model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+	}
+	p.config.Params.merge(p.params).apply(&req)
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to generate text with %s: %w", p.name, err)
+	}
+	if len(resp.Choices) == 0 {
+		return Completion{}, fmt.Errorf("failed to generate text with %s: no choices returned", p.name)
+	}
+	model := resp.Model
+	if model == "" {
+		model = p.config.Model
+	}
+	return Completion{Text: removeFirstAndLastLines(resp.Choices[0].Message.Content), Model: model, Params: p.config.Params.merge(p.params).record(), Usage: usageOf(resp.Usage)}, nil
+}
+
+func (p compatibleProvider) WithParams(params ModelParams) Provider {
+	return compatibleProvider{name: p.name, config: p.config, params: params}
+}
+
+// providers holds the available backends by name
+var providers = map[string]Provider{
+	defaultProviderName: openAIProvider{},
+}
+
+// RegisterProvider makes a backend available by its name, replacing any provider with the same name
+func RegisterProvider(p Provider) {
+	providers[p.Name()] = p
+}
+
+// NewCompatibleProvider creates a provider for an OpenAI-compatible endpoint
+func NewCompatibleProvider(name string, config ProviderConfig) (Provider, error) {
+	if name == "" {
+		return nil, fmt.Errorf("provider name must not be empty")
+	}
+	if config.BaseURL == "" || config.Model == "" {
+		return nil, fmt.Errorf("provider %s needs a base_url and a model", name)
+	}
+	return compatibleProvider{name: name, config: config}, nil
+}
+
+// lookupProvider returns the backend registered under name
+func lookupProvider(name string) (Provider, error) {
+	if p, ok := providers[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (available: %v)", name, providerNames())
+}
+
+// defaultProvider returns the backend that serves GenerateText, "openai" unless it was replaced
+func defaultProvider() Provider {
+	return providers[defaultProviderName]
+}
+
+// providerNames lists the registered providers in alphabetical order
+func providerNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetProvider selects the backend that code is sent to (default "openai")
+func (c *Converter) SetProvider(name string) {
+	c.provider = name
+}
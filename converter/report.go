@@ -0,0 +1,148 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Report summarises a conversion run with one entry per input file
+type Report struct {
+	TargetLanguage string       `json:"target_language"`
+	Files          []FileReport `json:"files"`
+
+	// Characterization lists the characterisation tests generated for untested sources
+	Characterization []CharacterizationResult `json:"characterization,omitempty"`
+
+	// Usage totals the requests and tokens sent to each model
+	Usage map[string]*ModelUsage `json:"usage,omitempty"`
+
+	// Params are the sampling settings sent with every request, when any were set
+	Params *ModelParams `json:"params,omitempty"`
+}
+
+// FileReport records what happened to a single input file
+type FileReport struct {
+	Source    string     `json:"source"`
+	Output    string     `json:"output"`
+	Action    string     `json:"action"`
+	Detection *Detection `json:"detection,omitempty"`
+	Test      bool       `json:"test,omitempty"`
+	Generated bool       `json:"generated,omitempty"`
+
+	// From and To describe the source dialect and target version, e.g. "Python 2" and "Python 3.12"
+	From       string            `json:"from,omitempty"`
+	To         string            `json:"to,omitempty"`
+	Validation *ValidationResult `json:"validation,omitempty"`
+	API        *APIParity        `json:"api,omitempty"`
+	Redactions []Redaction       `json:"redactions,omitempty"`
+	Provider   string            `json:"provider,omitempty"`
+	Egress     *EgressViolation  `json:"egress,omitempty"`
+
+	// Usage counts the requests and tokens spent on this file, by model
+	Usage map[string]*ModelUsage `json:"usage,omitempty"`
+
+	// Model is the model that produced the file, and Fallback lists the providers of the
+	// fallback chain that failed before it
+	Model    string            `json:"model,omitempty"`
+	Fallback []FallbackAttempt `json:"fallback,omitempty"`
+
+	// Samples rates every candidate generated when sampling is enabled
+	Samples []SampleScore `json:"samples,omitempty"`
+
+	// Review lists the discrepancies the reviewer found, when a review was requested
+	Review *Review `json:"review,omitempty"`
+}
+
+const (
+	actionConverted  = "converted"
+	actionCopied     = "copied"
+	actionModernized = "modernized"
+	actionUnchanged  = "unchanged"
+	actionSkipped    = "skipped"
+	actionBlocked    = "blocked"
+)
+
+// SetReportPath writes a JSON report of the run to path once conversion finishes;
+// an empty path disables the report file
+func (c *Converter) SetReportPath(path string) {
+	c.reportPath = path
+}
+
+// Report returns the per-file results of the last Convert call
+func (c *Converter) Report() Report {
+	return c.report
+}
+
+// newFileReport creates the report entry for a processed job
+func (c *Converter) newFileReport(job fileJob, action string) FileReport {
+	output := job.outputPath
+	if rel, err := filepath.Rel(c.outputDir, job.outputPath); err == nil {
+		output = rel
+	}
+
+	entry := FileReport{
+		Source:    filepath.ToSlash(job.relPath),
+		Output:    filepath.ToSlash(output),
+		Action:    action,
+		Test:      job.test,
+		Generated: job.generated,
+	}
+	// Files copied because their language is unknown have no detection to report
+	if job.detection.Language != "" {
+		detection := job.detection
+		entry.Detection = &detection
+	}
+	return entry
+}
+
+// writeReport logs how many files ended with each of the given actions and saves
+// the JSON report if a path was set
+func (c *Converter) writeReport(actions ...string) error {
+	if c.blockOnSecrets || c.policy != nil {
+		actions = append(actions, actionBlocked)
+	}
+	// Test files are counted and listed separately from the code they exercise
+	counts, testCounts := map[string]int{}, map[string]int{}
+	var tests []FileReport
+	for _, f := range c.report.Files {
+		if f.Test {
+			testCounts[f.Action]++
+			tests = append(tests, f)
+		} else {
+			counts[f.Action]++
+		}
+	}
+	c.logf("Summary: %s\n", summarizeActions(counts, actions))
+	if len(tests) > 0 {
+		c.logf("Tests: %s\n", summarizeActions(testCounts, actions))
+		for _, f := range tests {
+			c.logf("  %s -> %s\n", f.Source, f.Output)
+		}
+	}
+
+	if c.reportPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(c.reportPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", c.reportPath, err)
+	}
+	c.logf("Report written to %s\n", c.reportPath)
+	return nil
+}
+
+// summarizeActions formats action counts in the given order, e.g. "3 converted, 1 copied"
+func summarizeActions(counts map[string]int, actions []string) string {
+	summary := make([]string, len(actions))
+	for i, action := range actions {
+		summary[i] = fmt.Sprintf("%d %s", counts[action], action)
+	}
+	return strings.Join(summary, ", ")
+}
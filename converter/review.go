@@ -0,0 +1,162 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// noIssues is the reviewer's answer when the conversion is faithful
+const noIssues = "NO ISSUES"
+
+// findingPattern matches a finding line such as "- [high] line 12: off-by-one in the loop bound"
+var findingPattern = regexp.MustCompile(`(?i)^\s*(?:[-*]|\d+[.)])?\s*\[(high|medium|low)\]\s*(?:line\s+(\d+)\s*:)?\s*(.+)$`)
+
+// ReviewFinding is a semantic discrepancy between a source file and its conversion
+type ReviewFinding struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line,omitempty"`
+	Issue    string `json:"issue"`
+}
+
+// Review records what the reviewer found in a converted file and whether it was corrected
+type Review struct {
+	Provider  string           `json:"provider"`
+	Findings  []ReviewFinding  `json:"findings"`
+	Corrected bool             `json:"corrected,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Egress    *EgressViolation `json:"egress,omitempty"`
+}
+
+// SetReviewer has the named provider compare every converted file with its source
+// and list semantic discrepancies; an empty name disables the review
+func (c *Converter) SetReviewer(name string) {
+	c.reviewer = name
+}
+
+// SetReviewFix asks the reviewer for a corrected version of files with discrepancies,
+// instead of only attaching its findings to the report
+func (c *Converter) SetReviewFix(enabled bool) {
+	c.reviewFix = enabled
+}
+
+// reviewFile has the reviewer check a conversion and returns the code to keep, which
+// is the corrected version when fixes were requested. Review problems are recorded
+// in the review rather than failing the conversion.
+func (c *Converter) reviewFile(job fileJob, source, converted string, guidance ...string) (string, *Review) {
+	review := &Review{Provider: c.reviewer}
+	target := c.targetLang
+	lang, ok := languages.lookup(target)
+	if ok {
+		target = lang.Name
+	}
+
+	provider, violation, err := c.providerNamed(c.reviewer, job.relPath, job.sourceLang, source)
+	if err != nil {
+		review.Error = err.Error()
+		return converted, review
+	}
+	review.Egress = violation
+	if provider == nil {
+		review.Error = "the egress policy does not allow sending this file to the reviewer"
+		return converted, review
+	}
+	review.Provider = provider.Name()
+
+	findings, err := reviewUsingLLM(provider, source, job.sourceLang, converted, target)
+	if err != nil {
+		review.Error = err.Error()
+		c.logf("Warning: review of %s failed: %v\n", job.inputPath, err)
+		return converted, review
+	}
+	review.Findings = findings
+	if len(findings) == 0 {
+		c.logf("Review of %s found no discrepancies\n", job.outputPath)
+		return converted, review
+	}
+	c.logf("Review of %s found %d discrepancies:\n", job.outputPath, len(findings))
+	for _, f := range findings {
+		c.logf("  %s\n", f)
+	}
+	if !c.reviewFix {
+		return converted, review
+	}
+
+	corrected, err := fixUsingLLM(provider, source, job.sourceLang, converted, target, findings, c.promptGuidance(guidance...)...)
+	if err != nil {
+		review.Error = err.Error()
+		c.logf("Warning: correction of %s failed: %v\n", job.inputPath, err)
+		return converted, review
+	}
+	// A correction that breaks the syntax is worse than the discrepancies it fixes
+	if ok && checkSyntax(lang, converted) && !checkSyntax(lang, corrected) {
+		review.Error = "discarded the corrected version because its syntax check failed"
+		c.logf("Warning: %s for %s\n", review.Error, job.outputPath)
+		return converted, review
+	}
+	review.Corrected = true
+	c.logf("Applied the reviewer's corrections to %s\n", job.outputPath)
+	return corrected, review
+}
+
+// String formats a finding as the reviewer is asked to write it
+func (f ReviewFinding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("[%s] line %d: %s", f.Severity, f.Line, f.Issue)
+	}
+	return fmt.Sprintf("[%s] %s", f.Severity, f.Issue)
+}
+
+// reviewUsingLLM asks the reviewer to list semantic discrepancies between the source and its conversion
+func reviewUsingLLM(provider Provider, sourceCode, sourceLang, convertedCode, targetLang string) ([]ReviewFinding, error) {
+	prompt := fmt.Sprintf("Review the following conversion of %s code to %s. Compare the converted code with the original and list every semantic discrepancy: behaviour that differs, missing functionality, changed error handling and edge cases handled differently. Ignore differences that are only idiomatic. Write one finding per line in the form \"- [high|medium|low] line N: description\", where N is the line in the converted code, or reply with exactly %s.\n\nOriginal %s code:\n%s\n\nConverted %s code:\n%s", sourceLang, targetLang, noIssues, sourceLang, sourceCode, targetLang, convertedCode)
+
+	reply, err := provider.Generate(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review %s code: %w", targetLang, err)
+	}
+	return parseFindings(reply), nil
+}
+
+// fixUsingLLM asks for a version of the converted code that fixes the findings
+func fixUsingLLM(provider Provider, sourceCode, sourceLang, convertedCode, targetLang string, findings []ReviewFinding, guidance ...string) (string, error) {
+	var list strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&list, "- %s\n", f)
+	}
+	prompt := fmt.Sprintf("The following %s code was converted from %s, and a review found these discrepancies:\n%s\nOriginal %s code:\n%s\n\nConverted %s code:\n%s\n\nReturn a corrected version of the converted code that fixes them and keeps everything else. Just return the code, no other text.", targetLang, sourceLang, list.String(), sourceLang, sourceCode, targetLang, convertedCode)
+	if len(guidance) > 0 {
+		prompt += "\n\n" + strings.Join(guidance, "\n")
+	}
+
+	corrected, err := provider.Generate(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to correct %s code: %w", targetLang, err)
+	}
+	return corrected, nil
+}
+
+// parseFindings reads the reviewer's reply. A reply that is neither "NO ISSUES" nor in
+// the requested format is kept whole as a single finding of unknown severity.
+func parseFindings(reply string) []ReviewFinding {
+	reply = strings.TrimSpace(reply)
+	if reply == "" || strings.EqualFold(strings.Trim(reply, ".`* "), noIssues) {
+		return nil
+	}
+
+	var findings []ReviewFinding
+	for _, line := range strings.Split(reply, "\n") {
+		m := findingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		f := ReviewFinding{Severity: strings.ToLower(m[1]), Issue: strings.TrimSpace(m[3])}
+		f.Line, _ = strconv.Atoi(m[2])
+		findings = append(findings, f)
+	}
+	if len(findings) == 0 {
+		findings = append(findings, ReviewFinding{Severity: "unknown", Issue: reply})
+	}
+	return findings
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseFindings tests reading the reviewer's reply
+func TestParseFindings(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  []ReviewFinding
+	}{
+		{"NO ISSUES", nil},
+		{"No issues.", nil},
+		{"- [high] line 3: returns a - b\n* [Low] no docstring\n2. [medium] line 7: ignores errors",
+			[]ReviewFinding{{"high", 3, "returns a - b"}, {"low", 0, "no docstring"}, {"medium", 7, "ignores errors"}}},
+		{"Here is what I found:\n- [medium] line 1: int overflow is not checked\n", []ReviewFinding{{"medium", 1, "int overflow is not checked"}}},
+		{"The loop bound is off by one.", []ReviewFinding{{"unknown", 0, "The loop bound is off by one."}}},
+	}
+	for _, tt := range tests {
+		got := parseFindings(tt.reply)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseFindings(%q) = %+v, want %+v", tt.reply, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseFindings(%q)[%d] = %+v, want %+v", tt.reply, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// TestConvertReview tests attaching review findings to the report and applying corrections
+func TestConvertReview(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"math.go": "package math\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n",
+	})
+
+	converted := "def sub(a, b):\n    return a + b\n"
+	corrected := "def sub(a, b):\n    return a - b\n"
+	converterProvider, _ := NewFakeProvider("test-converter", FakeResponse{Response: converted})
+	reviewer, _ := NewFakeProvider("test-reviewer",
+		FakeResponse{Pattern: `^Review the following`, Response: "- [high] line 2: adds instead of subtracting"},
+		FakeResponse{Pattern: `review found these discrepancies`, Response: corrected},
+	)
+	for _, p := range []Provider{converterProvider, reviewer} {
+		RegisterProvider(p)
+		defer delete(providers, p.Name())
+	}
+
+	convert := func(fix bool) (*Converter, string) {
+		out := t.TempDir()
+		c := NewConverter(root, out, "python")
+		c.SetScaffold(false)
+		c.SetProvider("test-converter")
+		c.SetReviewer("test-reviewer")
+		c.SetReviewFix(fix)
+		c.SetLogOutput(io.Discard)
+		if err := c.Convert(); err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(out, "math.py"))
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		return c, string(data)
+	}
+
+	c, output := convert(false)
+	review := c.Report().Files[0].Review
+	if review == nil || len(review.Findings) != 1 || review.Findings[0].Line != 2 || review.Corrected {
+		t.Fatalf("Unexpected review: %+v", review)
+	}
+	if output != converted {
+		t.Errorf("Expected the conversion to be kept without fixes, got %q", output)
+	}
+	requests := reviewer.Requests()
+	if len(requests) != 1 || requests[0].File != "math.go" || !strings.Contains(requests[0].Prompt, "return a - b") || !strings.Contains(requests[0].Prompt, "return a + b") {
+		t.Errorf("Expected the reviewer to see the source and the conversion, got %+v", requests)
+	}
+
+	c, output = convert(true)
+	if review := c.Report().Files[0].Review; review == nil || !review.Corrected || review.Error != "" {
+		t.Errorf("Expected the correction to be applied: %+v", review)
+	}
+	if output != corrected {
+		t.Errorf("Expected the corrected version, got %q", output)
+	}
+	if usage := c.Report().Usage["unknown"]; usage == nil || usage.Requests != 3 {
+		t.Errorf("Expected the review requests to be metered, got %+v", usage)
+	}
+}
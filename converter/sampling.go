@@ -0,0 +1,243 @@
+package converter
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"unicode/utf8"
+)
+
+// Weights of the checks that rate a sampled candidate; a check that cannot run,
+// such as a validator that is not installed, adds nothing to any candidate
+const (
+	syntaxWeight  = 3.0
+	compileWeight = 4.0
+	apiWeight     = 2.0
+	lengthWeight  = 1.0
+)
+
+// Candidates whose non-blank line count is outside these multiples of the source's are
+// probably truncated or padded
+const (
+	minLengthRatio = 0.25
+	maxLengthRatio = 4.0
+)
+
+// SampleScore records how one candidate of a sampled conversion was rated
+type SampleScore struct {
+	Sample      int      `json:"sample"`
+	Temperature float32  `json:"temperature"`
+	Seed        int      `json:"seed"`
+	Syntax      *bool    `json:"syntax,omitempty"`
+	Compiles    *bool    `json:"compiles,omitempty"`
+	APIParity   *float64 `json:"api_parity,omitempty"`
+	LengthRatio float64  `json:"length_ratio"`
+	Score       float64  `json:"score"`
+	Chosen      bool     `json:"chosen,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// SetSamples generates n candidates for every converted file, varying temperature and
+// seed, and keeps the one that scores best; 1 or less converts each file once
+func (c *Converter) SetSamples(n int) {
+	c.samples = n
+}
+
+// sampleParams returns the parameters of the i-th sample: the first is as deterministic
+// as the model allows and later ones get warmer, each with its own seed
+func sampleParams(i int) ModelParams {
+	temperature := min(float32(i)*0.3, 1.2)
+	seed := i + 1
+	return ModelParams{Temperature: &temperature, Seed: &seed}
+}
+
+// convertSampled converts source once, or when sampling is enabled generates several
+// candidates and returns the best scoring one together with every candidate's score
+func (c *Converter) convertSampled(provider Provider, job fileJob, source string, guidance ...string) (string, []SampleScore, error) {
+	if c.samples <= 1 {
+		code, _, err := c.convertCode(provider, source, job.sourceLang, job.inputPath, guidance...)
+		return code, nil, err
+	}
+
+	var scores []SampleScore
+	var best string
+	var firstErr error
+	chosen := -1
+	for i := 0; i < c.samples; i++ {
+		params := sampleParams(i)
+		score := SampleScore{Sample: i + 1, Temperature: *params.Temperature, Seed: *params.Seed}
+		code, _, err := c.convertCode(withParams(provider, c.requestParams().merge(params)), source, job.sourceLang, job.inputPath, guidance...)
+		if err != nil {
+			score.Error = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			c.scoreSample(&score, job, source, code)
+			// Ties keep the earlier, cooler sample
+			if chosen < 0 || score.Score > scores[chosen].Score {
+				chosen, best = i, code
+			}
+		}
+		scores = append(scores, score)
+	}
+	if chosen < 0 {
+		return "", scores, firstErr
+	}
+	scores[chosen].Chosen = true
+	c.logf("Kept sample %d of %d for %s (score %.1f)\n", chosen+1, c.samples, job.inputPath, scores[chosen].Score)
+	return best, scores, nil
+}
+
+// scoreSample rates a candidate by syntax, compile success, API parity and length
+func (c *Converter) scoreSample(score *SampleScore, job fileJob, source, code string) {
+	lang, _ := languages.lookup(c.targetLang)
+
+	if lang != nil {
+		ok := checkSyntax(lang, code)
+		score.Syntax = &ok
+		if ok {
+			score.Score += syntaxWeight
+		}
+
+		if result := c.validateContent(job.outputPath, code); !result.Skipped {
+			score.Compiles = &result.Passed
+			if result.Passed {
+				score.Score += compileWeight
+			}
+		}
+
+		if parity := compareFileAPI(job.sourceLang, source, lang.Name, code); parity.Error == "" {
+			total := parity.Matched + len(parity.Missing) + len(parity.Renamed)
+			fraction := 1.0
+			if total > 0 {
+				fraction = float64(parity.Matched) / float64(total)
+			}
+			score.APIParity = &fraction
+			score.Score += apiWeight * fraction
+		}
+	}
+
+	if sourceLines := countCodeLines(source); sourceLines > 0 {
+		score.LengthRatio = float64(countCodeLines(code)) / float64(sourceLines)
+		if score.LengthRatio >= minLengthRatio && score.LengthRatio <= maxLengthRatio {
+			score.Score += lengthWeight
+		}
+	}
+}
+
+// countCodeLines counts the non-blank lines of code
+func countCodeLines(code string) int {
+	n := 0
+	for _, line := range strings.Split(code, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// checkSyntax parses Go with go/parser and checks that brackets are balanced in
+// other languages, outside strings and comments
+func checkSyntax(lang *Language, code string) bool {
+	if lang.Name == "Go" {
+		_, err := parser.ParseFile(token.NewFileSet(), "", code, parser.AllErrors)
+		return err == nil
+	}
+	return balancedDelimiters(lang, code)
+}
+
+// balancedDelimiters reports whether (), [] and {} nest correctly. Triple-quoted and
+// backtick strings may span lines; a single or double quote without a closing quote
+// on its line is treated as an ordinary character.
+func balancedDelimiters(lang *Language, code string) bool {
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	var stack []byte
+	for i := 0; i < len(code); i++ {
+		rest := code[i:]
+		switch {
+		case lang.LineComment != "" && strings.HasPrefix(rest, lang.LineComment) && startsComment(lang, code, i):
+			i += lineEnd(rest) - 1
+		case len(lang.BlockComment) == 2 && strings.HasPrefix(rest, lang.BlockComment[0]):
+			end := strings.Index(rest[len(lang.BlockComment[0]):], lang.BlockComment[1])
+			if end < 0 {
+				return false
+			}
+			i += len(lang.BlockComment[0]) + end + len(lang.BlockComment[1]) - 1
+		case strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''"):
+			end := strings.Index(rest[3:], rest[:3])
+			if end < 0 {
+				return false
+			}
+			i += 3 + end + 2
+		case code[i] == '\'' && lang.Name == "Rust":
+			// Single quotes start lifetimes as well as character literals
+			if end := charLiteralEnd(rest); end > 0 {
+				i += end
+			}
+		case code[i] == '"' || code[i] == '\'' || code[i] == '`':
+			if end := stringEnd(rest); end > 0 {
+				i += end
+			}
+		case code[i] == '(' || code[i] == '[' || code[i] == '{':
+			stack = append(stack, code[i])
+		case closing[code[i]] != 0:
+			if len(stack) == 0 || stack[len(stack)-1] != closing[code[i]] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return len(stack) == 0
+}
+
+// startsComment reports whether the line comment marker at code[i] starts a comment.
+// In shell scripts # only does so at the start of a word, so $#, ${#var} and a#b are not comments.
+func startsComment(lang *Language, code string, i int) bool {
+	if lang.Name != "Shell" || i == 0 {
+		return true
+	}
+	return strings.IndexByte(" \t\n;|&()", code[i-1]) >= 0
+}
+
+// lineEnd returns the index of the first newline in s, or its length
+func lineEnd(s string) int {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// stringEnd returns the index of the quote closing the string literal that s starts
+// with, or 0 when it is not closed (on the same line, except for backticks)
+func stringEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote != '`':
+			i++
+		case s[i] == quote:
+			return i
+		case s[i] == '\n' && quote != '`':
+			return 0
+		}
+	}
+	return 0
+}
+
+// charLiteralEnd returns the index of the quote closing a character literal of one
+// character or escape sequence at the start of s, or 0 when s starts a lifetime
+func charLiteralEnd(s string) int {
+	if len(s) > 3 && s[1] == '\\' {
+		// The escaped character itself may be a quote, as in '\''
+		if end := strings.IndexByte(s[3:], '\''); end >= 0 && end <= 8 {
+			return end + 3
+		}
+		return 0
+	}
+	_, size := utf8.DecodeRuneInString(s[1:])
+	if len(s) > 1+size && s[1+size] == '\'' {
+		return 1 + size
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// seededProvider answers with the response for the seed it was configured with
+type seededProvider struct {
+	responses map[int]string
+	params    ModelParams
+}
+
+func (p seededProvider) Name() string { return "test-seeded" }
+
+func (p seededProvider) Generate(prompt string) (string, error) {
+	if p.params.Seed == nil {
+		return p.responses[0], nil
+	}
+	return p.responses[*p.params.Seed], nil
+}
+
+func (p seededProvider) WithParams(params ModelParams) Provider {
+	return seededProvider{responses: p.responses, params: params}
+}
+
+// TestConvertSamples tests generating several candidates per file and keeping the best one
+func TestConvertSamples(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"util/math.go": "package util\n\n// Add returns the sum\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+	})
+
+	good := "def add(a, b):\n    \"\"\"Return the sum (of a and b).\"\"\"\n    return a + b\n"
+	RegisterProvider(seededProvider{responses: map[int]string{
+		0: good,
+		1: "def add(a, b):\n    return (a + b\n",
+		2: good,
+		3: "def plus(a, b):\n    return a + b\n",
+	}})
+	defer delete(providers, "test-seeded")
+
+	out := t.TempDir()
+	c := NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetProvider("test-seeded")
+	c.SetSamples(3)
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	converted, err := os.ReadFile(filepath.Join(out, "util", "math.py"))
+	if err != nil || string(converted) != good {
+		t.Errorf("Expected the well-formed sample to be kept, got %q (%v)", converted, err)
+	}
+
+	samples := c.Report().Files[0].Samples
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 scored samples, got %+v", samples)
+	}
+	if !samples[1].Chosen || samples[0].Chosen || samples[2].Chosen {
+		t.Errorf("Expected sample 2 to be chosen: %+v", samples)
+	}
+	if samples[0].Syntax == nil || *samples[0].Syntax || samples[0].Score >= samples[1].Score {
+		t.Errorf("Expected the unbalanced sample to score lower: %+v", samples[0])
+	}
+	if samples[2].APIParity == nil || *samples[2].APIParity == 1 || samples[2].Score >= samples[1].Score {
+		t.Errorf("Expected the renamed function to lower API parity: %+v", samples[2])
+	}
+	if samples[0].Temperature != 0 || samples[1].Temperature <= 0 || samples[2].Seed != 3 {
+		t.Errorf("Expected varied temperatures and seeds: %+v", samples)
+	}
+
+	// Without sampling the provider is asked once, with its default parameters
+	out = t.TempDir()
+	c = NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetProvider("test-seeded")
+	c.SetLogOutput(io.Discard)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if samples := c.Report().Files[0].Samples; samples != nil {
+		t.Errorf("Expected no samples without sampling, got %+v", samples)
+	}
+}
+
+// TestCheckSyntax tests the syntax check used to rate samples
+func TestCheckSyntax(t *testing.T) {
+	tests := []struct {
+		lang, code string
+		want       bool
+	}{
+		{"go", "package main\n\nfunc main() {}\n", true},
+		{"go", "package main\n\nfunc main() {\n", false},
+		{"python", "print('(')  # )\n", true},
+		{"python", "def f():\n    \"\"\"Docs with ( only.\"\"\"\n    return [1, 2]\n", true},
+		{"python", "x = [1, 2\n", false},
+		{"javascript", "const s = `multi\n}line`;\n/* { */ f({a: [1]});\n", true},
+		{"javascript", "function f() { return g(]; }\n", false},
+		{"rust", "fn first<'a>(s: &'a str) -> &'a str { &s[..1] }\n", true},
+		{"rust", "let c = ['(', '\\'', '\\u{28}'];\n", true},
+		{"bash", "if [ $# -gt 0 ]; then echo \"${#1} ${1#pre}\"; fi # (\n", true},
+		{"bash", "echo $# )\n", false},
+	}
+	for _, tt := range tests {
+		lang, err := ResolveLanguage(tt.lang)
+		if err != nil {
+			t.Fatalf("ResolveLanguage(%s) error = %v", tt.lang, err)
+		}
+		if got := checkSyntax(lang, tt.code); got != tt.want {
+			t.Errorf("checkSyntax(%s, %q) = %v, want %v", tt.lang, tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,540 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// projectMetadata describes the source project being converted
+type projectMetadata struct {
+	Name string
+	// Module is the module path of a Go source project, which converted Go code may keep importing
+	Module      string
+	Version     string
+	Description string
+	EntryPoints []string
+	// Tests reports whether any test files were converted
+	Tests bool
+}
+
+// writeScaffold generates a manifest and README for the target language in the output directory
+func (c *Converter) writeScaffold() error {
+	meta := readSourceMetadata(c.inputDir)
+	meta.EntryPoints = c.entryPoints()
+	for _, f := range c.converted {
+		meta.Tests = meta.Tests || f.test
+	}
+	deps := c.collectDependencies(meta)
+
+	ext := getTargetExtension(c.targetLang)
+	manifestName, manifest := renderManifest(ext, c.target, c.layout, meta, deps)
+	if manifestName != "" {
+		if err := c.writeIfAbsent(filepath.Join(c.outputDir, manifestName), manifest); err != nil {
+			return err
+		}
+	}
+
+	return c.writeIfAbsent(filepath.Join(c.outputDir, "README.md"), renderReadme(ext, meta, manifestName))
+}
+
+// writeIfAbsent writes content to path unless a file already exists there
+func (c *Converter) writeIfAbsent(path, content string) error {
+	if _, err := os.Stat(path); err == nil {
+		c.logf("Skipping %s: file already exists\n", path)
+		return nil
+	}
+
+	c.logf("Generating %s\n", path)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", path, err)
+	}
+	return nil
+}
+
+// readSourceMetadata extracts the project name, version and description from the source manifests
+func readSourceMetadata(inputDir string) projectMetadata {
+	meta := projectMetadata{
+		Name:    filepath.Base(inputDir),
+		Version: "0.1.0",
+	}
+
+	if data, err := os.ReadFile(filepath.Join(inputDir, "package.json")); err == nil {
+		var pkg struct {
+			Name        string `json:"name"`
+			Version     string `json:"version"`
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			meta.Name = firstNonEmpty(pkg.Name, meta.Name)
+			meta.Version = firstNonEmpty(pkg.Version, meta.Version)
+			meta.Description = pkg.Description
+		}
+		return meta
+	}
+
+	if data, err := os.ReadFile(filepath.Join(inputDir, "go.mod")); err == nil {
+		if m := regexp.MustCompile(`(?m)^module\s+(\S+)`).FindSubmatch(data); m != nil {
+			meta.Module = string(m[1])
+			meta.Name = filepath.Base(meta.Module)
+		}
+		return meta
+	}
+
+	for _, name := range []string{"pyproject.toml", "Cargo.toml"} {
+		data, err := os.ReadFile(filepath.Join(inputDir, name))
+		if err != nil {
+			continue
+		}
+		meta.Name = firstNonEmpty(tomlString(data, "name"), meta.Name)
+		meta.Version = firstNonEmpty(tomlString(data, "version"), meta.Version)
+		meta.Description = tomlString(data, "description")
+		return meta
+	}
+
+	if data, err := os.ReadFile(filepath.Join(inputDir, "pom.xml")); err == nil {
+		// The first artifactId and version after the parent block belong to the project
+		text := regexp.MustCompile(`(?s)<parent>.*?</parent>`).ReplaceAllString(string(data), "")
+		meta.Name = firstNonEmpty(xmlString(text, "artifactId"), meta.Name)
+		meta.Version = firstNonEmpty(xmlString(text, "version"), meta.Version)
+		meta.Description = xmlString(text, "description")
+	}
+
+	return meta
+}
+
+// tomlString returns the first top-level string value for key in a TOML document
+func tomlString(data []byte, key string) string {
+	re := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(key) + `\s*=\s*"([^"]*)"`)
+	if m := re.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// xmlString returns the text of the first element with the given tag
+func xmlString(text, tag string) string {
+	re := regexp.MustCompile(`<` + tag + `>\s*([^<]*?)\s*</` + tag + `>`)
+	if m := re.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// entryPointPatterns recognises program entry points in source files
+var entryPointPatterns = map[string]*regexp.Regexp{
+	"Go":         regexp.MustCompile(`(?m)^func main\(\)`),
+	"Python":     regexp.MustCompile(`__name__\s*==\s*['"]__main__['"]`),
+	"Java":       regexp.MustCompile(`public\s+static\s+void\s+main\s*\(`),
+	"C":          regexp.MustCompile(`\bint\s+main\s*\(`),
+	"C++":        regexp.MustCompile(`\bint\s+main\s*\(`),
+	"C#":         regexp.MustCompile(`static\s+(async\s+)?\w+\s+Main\s*\(`),
+	"Rust":       regexp.MustCompile(`(?m)^fn main\(\)`),
+	"Kotlin":     regexp.MustCompile(`(?m)^fun main\(`),
+	"Swift":      regexp.MustCompile(`@main\b`),
+	"JavaScript": regexp.MustCompile(`require\.main\s*===\s*module`),
+}
+
+// entryPoints returns the output paths, relative to the output directory, of converted entry points
+func (c *Converter) entryPoints() []string {
+	var entries []string
+	for _, f := range c.converted {
+		re, ok := entryPointPatterns[f.sourceLang]
+		if !ok || !re.MatchString(f.source) {
+			continue
+		}
+		if rel, err := filepath.Rel(c.outputDir, f.outputPath); err == nil {
+			entries = append(entries, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// importPatterns extract imported module names from converted code, keyed by target extension
+var importPatterns = map[string][]*regexp.Regexp{
+	".py": {
+		regexp.MustCompile(`(?m)^\s*import\s+([A-Za-z_][\w]*)`),
+		regexp.MustCompile(`(?m)^\s*from\s+([A-Za-z_][\w]*)[\w.]*\s+import\b`),
+	},
+	".js": {
+		regexp.MustCompile(`require\(\s*['"]([^'"]+)['"]\s*\)`),
+		regexp.MustCompile(`(?m)^\s*import\s+(?:[^'"]*?\s+from\s+)?['"]([^'"]+)['"]`),
+	},
+	".go": {
+		regexp.MustCompile(`(?m)^\s*(?:import\s+)?(?:[\w.]+\s+)?"([^"]+)"\s*$`),
+	},
+	".rs": {
+		regexp.MustCompile(`(?m)^\s*(?:pub\s+)?use\s+([A-Za-z_]\w*)::`),
+		regexp.MustCompile(`(?m)^\s*extern\s+crate\s+([A-Za-z_]\w*)`),
+	},
+}
+
+func init() {
+	importPatterns[".ts"] = importPatterns[".js"]
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// rustModDecl matches the module declarations of Rust code, whose modules are local to the crate
+var rustModDecl = regexp.MustCompile(`(?m)^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+([A-Za-z_]\w*)`)
+
+// collectDependencies returns the sorted third-party packages imported by the converted files
+func (c *Converter) collectDependencies(meta projectMetadata) []string {
+	ext := getTargetExtension(c.targetLang)
+	patterns := importPatterns[ext]
+	local := c.localModules(ext, meta)
+
+	seen := map[string]bool{}
+	for _, f := range c.converted {
+		for _, re := range patterns {
+			for _, m := range re.FindAllStringSubmatch(f.output, -1) {
+				if dep, ok := externalDependency(ext, m[1], local); ok {
+					seen[dep] = true
+				}
+			}
+		}
+	}
+
+	deps := make([]string, 0, len(seen))
+	for dep := range seen {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// localModules returns the names that imports of the converted code use for the project
+// itself: the top-level modules and packages of the output tree, the modules Rust code
+// declares, and the module paths of a Go project
+func (c *Converter) localModules(ext string, meta projectMetadata) map[string]bool {
+	local := map[string]bool{}
+	add := func(rel string, dir bool) {
+		first := strings.Split(filepath.ToSlash(rel), "/")[0]
+		if !dir && first == filepath.Base(rel) {
+			first = strings.TrimSuffix(first, filepath.Ext(first))
+		}
+		local[first] = true
+	}
+	for _, dir := range []string{c.outputDir, filepath.Join(c.outputDir, "src")} {
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			add(e.Name(), e.IsDir())
+		}
+	}
+	for _, f := range c.converted {
+		if rel, err := filepath.Rel(c.outputDir, f.outputPath); err == nil && !strings.HasPrefix(rel, "..") {
+			add(rel, false)
+		}
+		if ext == ".rs" {
+			for _, m := range rustModDecl.FindAllStringSubmatch(f.output, -1) {
+				local[m[1]] = true
+			}
+		}
+	}
+	if ext == ".go" {
+		local[packageName(meta.Name)] = true
+		if meta.Module != "" {
+			local[meta.Module] = true
+		}
+	}
+	return local
+}
+
+// externalDependency maps an import to its package name, reporting false for standard
+// library imports and for imports of the project's own modules
+func externalDependency(ext, name string, local map[string]bool) (string, bool) {
+	switch ext {
+	case ".py":
+		if pythonStdlib[name] || local[name] {
+			return "", false
+		}
+		if dist, ok := pythonDistributions[name]; ok {
+			return dist, true
+		}
+		return name, true
+	case ".js", ".ts":
+		if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "node:") {
+			return "", false
+		}
+		parts := strings.Split(name, "/")
+		if strings.HasPrefix(name, "@") && len(parts) > 1 {
+			return parts[0] + "/" + parts[1], true
+		}
+		return parts[0], !nodeBuiltins[parts[0]]
+	case ".go":
+		for module := range local {
+			if name == module || strings.HasPrefix(name, module+"/") {
+				return "", false
+			}
+		}
+		// Standard library import paths never contain a dot in their first element
+		first := strings.Split(name, "/")[0]
+		return name, strings.Contains(first, ".")
+	case ".rs":
+		switch name {
+		case "std", "core", "alloc", "crate", "self", "super":
+			return "", false
+		}
+		return name, !local[name]
+	}
+	return "", false
+}
+
+// packageName normalises a project name into a lowercase, hyphen-separated package name
+func packageName(name string) string {
+	name = strings.ToLower(name)
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "converted-project"
+	}
+	return name
+}
+
+// renderManifest returns the manifest file name and contents for the target language,
+// declaring the requested target version where the manifest has one
+func renderManifest(ext string, target TargetSpec, layout Layout, meta projectMetadata, deps []string) (string, string) {
+	name := packageName(meta.Name)
+	var b strings.Builder
+
+	switch ext {
+	case ".go":
+		fmt.Fprintf(&b, "module %s\n\ngo %s\n", name, firstNonEmpty(target.Version, "1.23"))
+		if len(deps) > 0 {
+			b.WriteString("\n// Run `go mod tidy` to resolve these imports:\n")
+			for _, dep := range deps {
+				fmt.Fprintf(&b, "//   %s\n", dep)
+			}
+		}
+		return "go.mod", b.String()
+
+	case ".js", ".ts":
+		pkg := map[string]interface{}{
+			"name":         name,
+			"version":      meta.Version,
+			"description":  meta.Description,
+			"private":      true,
+			"dependencies": versionMap(deps, "*"),
+		}
+		if len(meta.EntryPoints) > 0 {
+			pkg["main"] = meta.EntryPoints[0]
+			pkg["scripts"] = map[string]string{"start": runCommand(ext, meta.EntryPoints[0])}
+		}
+		if ext == ".ts" {
+			pkg["devDependencies"] = map[string]string{"typescript": "^5.0.0", "tsx": "^4.0.0"}
+		}
+		data, _ := json.MarshalIndent(pkg, "", "  ")
+		return "package.json", string(data) + "\n"
+
+	case ".py":
+		fmt.Fprintf(&b, "[project]\nname = %q\nversion = %q\n", name, meta.Version)
+		if meta.Description != "" {
+			fmt.Fprintf(&b, "description = %q\n", meta.Description)
+		}
+		fmt.Fprintf(&b, "requires-python = \">=%s\"\ndependencies = [\n", firstNonEmpty(target.Version, "3.9"))
+		for _, dep := range deps {
+			fmt.Fprintf(&b, "    %q,\n", dep)
+		}
+		b.WriteString("]\n\n[build-system]\nrequires = [\"setuptools>=61\"]\nbuild-backend = \"setuptools.build_meta\"\n")
+		return "pyproject.toml", b.String()
+
+	case ".rs":
+		fmt.Fprintf(&b, "[package]\nname = %q\nversion = %q\nedition = \"2021\"\n", strings.ReplaceAll(name, "-", "_"), meta.Version)
+		if meta.Description != "" {
+			fmt.Fprintf(&b, "description = %q\n", meta.Description)
+		}
+		for _, entry := range meta.EntryPoints {
+			bin := strings.TrimSuffix(filepath.Base(entry), filepath.Ext(entry))
+			fmt.Fprintf(&b, "\n[[bin]]\nname = %q\npath = %q\n", bin, entry)
+		}
+		b.WriteString("\n[dependencies]\n")
+		for _, dep := range deps {
+			fmt.Fprintf(&b, "%s = \"*\"\n", dep)
+		}
+		return "Cargo.toml", b.String()
+
+	case ".java":
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  <properties>
+    <maven.compiler.release>%s</maven.compiler.release>
+    <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>
+  </properties>
+%s</project>
+`, name, meta.Version, javaRelease(target.Version), pomBuild(layout, meta.Tests))
+		return "pom.xml", b.String()
+	}
+
+	return "", ""
+}
+
+// junitVersion is the JUnit 5 release that converted Java tests are declared against
+const junitVersion = "5.10.2"
+
+// pomBuild returns the dependencies and build section of a pom.xml. Only the idiomatic
+// layout keeps Maven's src/main/java and src/test/java directories; the mirror and flat
+// layouts compile from the project root, with tests left out of the main sources.
+func pomBuild(layout Layout, tests bool) string {
+	var b strings.Builder
+	if tests {
+		fmt.Fprintf(&b, `  <dependencies>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <version>%s</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+`, junitVersion)
+	}
+
+	var dirs, compiler string
+	if layout != LayoutIdiomatic {
+		dirs = "    <sourceDirectory>.</sourceDirectory>\n"
+	}
+	switch {
+	case tests && layout == LayoutFlat:
+		dirs += "    <testSourceDirectory>.</testSourceDirectory>\n"
+		compiler = `          <excludes>
+            <exclude>**/*Test.java</exclude>
+          </excludes>
+          <testIncludes>
+            <testInclude>**/*Test.java</testInclude>
+          </testIncludes>
+`
+	case tests && layout != LayoutIdiomatic:
+		compiler = `          <excludes>
+            <exclude>src/test/**</exclude>
+          </excludes>
+`
+	}
+	if dirs == "" && !tests {
+		return ""
+	}
+
+	b.WriteString("  <build>\n" + dirs)
+	if tests {
+		b.WriteString("    <plugins>\n")
+		if compiler != "" {
+			b.WriteString(`      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.13.0</version>
+        <configuration>
+` + compiler + `        </configuration>
+      </plugin>
+`)
+		}
+		b.WriteString(`      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.5</version>
+      </plugin>
+    </plugins>
+`)
+	}
+	b.WriteString("  </build>\n")
+	return b.String()
+}
+
+// javaRelease turns a Java target version into a compiler release, so that 1.8 becomes 8
+func javaRelease(version string) string {
+	if version == "" {
+		return "17"
+	}
+	return strings.TrimPrefix(version, "1.")
+}
+
+// versionMap maps every dependency to the same version constraint
+func versionMap(deps []string, version string) map[string]string {
+	m := make(map[string]string, len(deps))
+	for _, dep := range deps {
+		m[dep] = version
+	}
+	return m
+}
+
+// buildCommands lists how to install dependencies and build a project for each target extension
+var buildCommands = map[string]string{
+	".go":   "go mod tidy\ngo build ./...",
+	".js":   "npm install",
+	".ts":   "npm install\nnpx tsc --noEmit",
+	".py":   "python -m venv .venv\n. .venv/bin/activate\npip install -e .",
+	".rs":   "cargo build",
+	".java": "mvn compile",
+}
+
+// runCommand returns the command that runs the given entry point
+func runCommand(ext, entry string) string {
+	switch ext {
+	case ".go":
+		return "go run ./" + filepath.ToSlash(filepath.Dir(entry))
+	case ".js":
+		return "node " + entry
+	case ".ts":
+		return "npx tsx " + entry
+	case ".py":
+		return "python " + entry
+	case ".rs":
+		return "cargo run --bin " + strings.TrimSuffix(filepath.Base(entry), filepath.Ext(entry))
+	case ".java":
+		return "java " + entry
+	}
+	return ""
+}
+
+// renderReadme describes how to build and run the converted project
+func renderReadme(ext string, meta projectMetadata, manifestName string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", meta.Name)
+	if meta.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", meta.Description)
+	}
+	b.WriteString("This project was generated by code-converter-cli. Review the converted code before relying on it.\n")
+
+	if cmd, ok := buildCommands[ext]; ok {
+		b.WriteString("\n## Build\n\n")
+		if manifestName != "" {
+			fmt.Fprintf(&b, "Dependencies are declared in `%s`.\n\n", manifestName)
+		}
+		fmt.Fprintf(&b, "```bash\n%s\n```\n", cmd)
+	}
+
+	var runs []string
+	for _, entry := range meta.EntryPoints {
+		if cmd := runCommand(ext, entry); cmd != "" {
+			runs = append(runs, cmd)
+		}
+	}
+	if len(runs) > 0 {
+		fmt.Fprintf(&b, "\n## Run\n\n```bash\n%s\n```\n", strings.Join(runs, "\n"))
+	}
+
+	return b.String()
+}
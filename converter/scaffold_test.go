@@ -0,0 +1,175 @@
+package converter
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadSourceMetadata tests that project metadata is read from source manifests
+func TestReadSourceMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		wantName string
+		wantVer  string
+	}{
+		{"go.mod", "go.mod", "module github.com/acme/widget\n\ngo 1.21\n", "widget", "0.1.0"},
+		{"package.json", "package.json", `{"name": "widget-js", "version": "2.3.4"}`, "widget-js", "2.3.4"},
+		{"pyproject", "pyproject.toml", "[project]\nname = \"widget-py\"\nversion = \"1.0.0\"\n", "widget-py", "1.0.0"},
+		{"pom.xml", "pom.xml", "<project><parent><artifactId>base</artifactId><version>9</version></parent><artifactId>widget-java</artifactId><version>3.1</version></project>", "widget-java", "3.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write manifest: %v", err)
+			}
+
+			meta := readSourceMetadata(dir)
+			if meta.Name != tt.wantName || meta.Version != tt.wantVer {
+				t.Errorf("readSourceMetadata() = (%q, %q), want (%q, %q)", meta.Name, meta.Version, tt.wantName, tt.wantVer)
+			}
+		})
+	}
+}
+
+// TestCollectDependencies tests that only third-party imports are collected from converted code
+func TestCollectDependencies(t *testing.T) {
+	c := NewConverter("in", "out", "python")
+	c.converted = []convertedFile{
+		{output: "import os\nimport requests\nfrom flask import Flask\nfrom . import local\n"},
+		{output: "import json\nimport requests\n"},
+	}
+
+	got := c.collectDependencies(projectMetadata{})
+	want := []string{"flask", "requests"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectDependencies() = %v, want %v", got, want)
+	}
+
+	// Imports of the project's own modules are not dependencies
+	tests := []struct {
+		target string
+		meta   projectMetadata
+		files  []convertedFile
+		want   []string
+	}{
+		{"python", projectMetadata{}, []convertedFile{
+			{outputPath: filepath.Join("out", "util", "greet.py"), output: "import requests\n"},
+			{outputPath: filepath.Join("out", "main.py"), output: "from util.greet import hello\nimport greet_tools\n"},
+		}, []string{"greet_tools", "requests"}},
+		{"python", projectMetadata{}, []convertedFile{
+			{outputPath: filepath.Join("out", "main.py"), output: "import fnmatch\nimport tomllib\nfrom email.message import Message\nimport yaml\nfrom PIL import Image\nfrom sklearn.cluster import KMeans\n"},
+		}, []string{"Pillow", "PyYAML", "scikit-learn"}},
+		{"javascript", projectMetadata{}, []convertedFile{
+			{outputPath: filepath.Join("out", "main.js"), output: "const m = require('module');\nimport { createHook } from 'async_hooks';\nimport diag from 'diagnostics_channel';\nimport test from 'node:test';\nimport { readFile } from 'fs/promises';\nimport express from 'express';\n"},
+		}, []string{"express"}},
+		{"rust", projectMetadata{}, []convertedFile{
+			{outputPath: filepath.Join("out", "src", "main.rs"), output: "mod parser;\npub(crate) mod lexer;\nuse parser::parse;\nuse lexer::Token;\nuse serde::Serialize;\n"},
+		}, []string{"serde"}},
+		{"go", projectMetadata{Name: "tool", Module: "github.com/acme/tool"}, []convertedFile{
+			{outputPath: filepath.Join("out", "main.go"), output: "import (\n\t\"fmt\"\n\t\"github.com/acme/tool/util\"\n\t\"tool/internal/db\"\n\t\"github.com/spf13/cobra\"\n)\n"},
+		}, []string{"github.com/spf13/cobra"}},
+	}
+	for _, tt := range tests {
+		c := NewConverter("in", "out", tt.target)
+		c.converted = tt.files
+		if got := c.collectDependencies(tt.meta); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("collectDependencies(%s) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestRenderPomLayout tests that pom.xml only moves the source directory for the mirror and flat layouts and declares JUnit for converted tests
+func TestRenderPomLayout(t *testing.T) {
+	tests := []struct {
+		layout  Layout
+		tests   bool
+		want    []string
+		notWant []string
+	}{
+		{LayoutIdiomatic, false, nil, []string{"<build>", "sourceDirectory", "junit"}},
+		{LayoutIdiomatic, true, []string{"<artifactId>junit-jupiter</artifactId>", "<scope>test</scope>", "maven-surefire-plugin"}, []string{"sourceDirectory", "<excludes>"}},
+		{LayoutMirror, false, []string{"<sourceDirectory>.</sourceDirectory>"}, []string{"junit", "<plugins>"}},
+		{LayoutMirror, true, []string{"<sourceDirectory>.</sourceDirectory>", "<exclude>src/test/**</exclude>", "junit-jupiter"}, []string{"testSourceDirectory"}},
+		{LayoutFlat, true, []string{"<sourceDirectory>.</sourceDirectory>", "<testSourceDirectory>.</testSourceDirectory>", "<testInclude>**/*Test.java</testInclude>"}, nil},
+	}
+	for _, tt := range tests {
+		meta := projectMetadata{Name: "demo", Version: "1.0.0", Tests: tt.tests}
+		_, pom := renderManifest(".java", ParseTargetSpec("java"), tt.layout, meta, nil)
+		if err := xml.Unmarshal([]byte(pom), new(struct{})); err != nil {
+			t.Errorf("pom.xml for %s layout (tests %v) is not well-formed: %v\n%s", tt.layout, tt.tests, err, pom)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(pom, want) {
+				t.Errorf("pom.xml for %s layout (tests %v) does not contain %q:\n%s", tt.layout, tt.tests, want, pom)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(pom, notWant) {
+				t.Errorf("pom.xml for %s layout (tests %v) contains %q:\n%s", tt.layout, tt.tests, notWant, pom)
+			}
+		}
+	}
+}
+
+// TestRenderManifestVersion tests that manifests declare the requested target version
+func TestRenderManifestVersion(t *testing.T) {
+	tests := []struct {
+		target, want string
+	}{
+		{"go", "go 1.23\n"},
+		{"go@1.22", "go 1.22\n"},
+		{"java", "<maven.compiler.release>17<"},
+		{"java@21", "<maven.compiler.release>21<"},
+		{"java@1.8", "<maven.compiler.release>8<"},
+		{"python@3.12", `requires-python = ">=3.12"`},
+	}
+	for _, tt := range tests {
+		spec := ParseTargetSpec(tt.target)
+		_, manifest := renderManifest(getTargetExtension(spec.Name), spec, LayoutMirror, projectMetadata{Name: "demo", Version: "1.0.0"}, nil)
+		if !strings.Contains(manifest, tt.want) {
+			t.Errorf("renderManifest(%s) does not contain %q:\n%s", tt.target, tt.want, manifest)
+		}
+	}
+}
+
+// TestScaffoldGeneration tests that a manifest and README are generated after directory conversion
+func TestScaffoldGeneration(t *testing.T) {
+	tempInput := t.TempDir()
+	tempOutput := t.TempDir()
+
+	source := "package main\n\nfunc main() {\n}\n"
+	if err := os.WriteFile(filepath.Join(tempInput, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cleanup := setupMockGPT("import requests\n\nprint('hi')", nil)
+	defer cleanup()
+
+	if err := NewConverter(tempInput, tempOutput, "python").Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(tempOutput, "pyproject.toml"))
+	if err != nil {
+		t.Fatalf("Expected pyproject.toml to be generated: %v", err)
+	}
+	if !strings.Contains(string(manifest), `"requests"`) {
+		t.Errorf("pyproject.toml does not declare the requests dependency:\n%s", manifest)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(tempOutput, "README.md"))
+	if err != nil {
+		t.Fatalf("Expected README.md to be generated: %v", err)
+	}
+	if !strings.Contains(string(readme), "python main.py") {
+		t.Errorf("README.md does not describe how to run main.py:\n%s", readme)
+	}
+}
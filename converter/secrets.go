@@ -0,0 +1,200 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretPlaceholderPrefix starts every placeholder that stands in for a redacted secret
+const secretPlaceholderPrefix = "REDACTED_SECRET_"
+
+// Redaction records a secret that was replaced with a placeholder before the
+// code was sent to the model. The secret itself is never written to the report.
+type Redaction struct {
+	Rule        string `json:"rule"`
+	Line        int    `json:"line"`
+	Placeholder string `json:"placeholder"`
+	Restored    bool   `json:"restored"`
+
+	value string
+}
+
+// secretRule detects one kind of secret. When the pattern has a capture group
+// only the group is redacted, so "password = 'hunter2'" keeps its key.
+type secretRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var secretRules = []secretRule{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Stripe key", regexp.MustCompile(`\b(?:sk|rk)_live_[0-9A-Za-z]{24,}\b`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"connection string password", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@'"]+:([^\s@'"/]+)@`)},
+	{"password assignment", regexp.MustCompile(`(?i)\b[\w.-]*(?:password|passwd|pwd|secret|api_?key|apikey|access_?key|auth_?token|token|credentials?)\w*["']?\s*(?::=|=>|[:=])\s*["'\x60]([^"'\x60\s]{4,})["'\x60]`)},
+}
+
+// highEntropyString matches quoted literals that may hold a random key
+var highEntropyString = regexp.MustCompile(`["'\x60]([A-Za-z0-9+/=_-]{20,})["'\x60]`)
+
+var hexString = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// SetBlockOnSecrets refuses to send files that contain secrets to the model
+// instead of redacting them. Blocked files are left out of the output and
+// listed in the report.
+func (c *Converter) SetBlockOnSecrets(enabled bool) {
+	c.blockOnSecrets = enabled
+}
+
+// redactSecrets replaces every secret in content with a placeholder derived from
+// its value, so the same secret gets the same placeholder in every file and run
+func redactSecrets(content string) (string, []Redaction) {
+	type match struct {
+		start, end int
+		rule       string
+	}
+	var matches []match
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rule := range secretRules {
+		for _, loc := range rule.re.FindAllStringSubmatchIndex(content, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if strings.HasPrefix(content[start:end], secretPlaceholderPrefix) || overlaps(start, end) {
+				continue
+			}
+			matches = append(matches, match{start, end, rule.name})
+		}
+	}
+	for _, loc := range highEntropyString.FindAllStringSubmatchIndex(content, -1) {
+		start, end := loc[2], loc[3]
+		if looksRandom(content[start:end]) && !overlaps(start, end) {
+			matches = append(matches, match{start, end, "high-entropy string"})
+		}
+	}
+	if len(matches) == 0 {
+		return content, nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	var b strings.Builder
+	var redactions []Redaction
+	last := 0
+	for _, m := range matches {
+		value := content[m.start:m.end]
+		sum := sha256.Sum256([]byte(value))
+		placeholder := secretPlaceholderPrefix + strings.ToUpper(hex.EncodeToString(sum[:4]))
+		redactions = append(redactions, Redaction{
+			Rule:        m.rule,
+			Line:        strings.Count(content[:m.start], "\n") + 1,
+			Placeholder: placeholder,
+			value:       value,
+		})
+		b.WriteString(content[last:m.start])
+		b.WriteString(placeholder)
+		last = m.end
+	}
+	b.WriteString(content[last:])
+	return b.String(), redactions
+}
+
+// restoreSecrets puts the redacted secrets back into code returned by the model,
+// marking which placeholders were found
+func restoreSecrets(code string, redactions []Redaction) string {
+	for i, r := range redactions {
+		if strings.Contains(code, r.Placeholder) {
+			code = strings.ReplaceAll(code, r.Placeholder, r.value)
+			redactions[i].Restored = true
+		}
+	}
+	return code
+}
+
+// looksRandom reports whether a literal has the character mix and Shannon
+// entropy of a generated key rather than of words or identifiers
+func looksRandom(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") || strings.Trim(s, "0123456789") == "" {
+		return false
+	}
+	if hexString.MatchString(s) {
+		return len(s) >= 32 && shannonEntropy(s) >= 3.0
+	}
+	hasUpper := strings.ToLower(s) != s
+	hasLower := strings.ToUpper(s) != s
+	return hasUpper && hasLower && shannonEntropy(s) >= 4.0
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// secretGuidance tells the model to keep placeholders intact so the secrets can be restored
+func secretGuidance(redactions []Redaction) []string {
+	if len(redactions) == 0 {
+		return nil
+	}
+	return []string{"Identifiers starting with " + secretPlaceholderPrefix + " stand in for redacted secrets; copy them into the converted code exactly as they are, in the same places."}
+}
+
+// redact prepares file content for the model. It returns the redacted content and
+// its redactions, or blocked when the file contains secrets and must not be sent.
+func (c *Converter) redact(content, path string) (redacted string, redactions []Redaction, blocked bool) {
+	redacted, redactions = redactSecrets(content)
+	if len(redactions) == 0 {
+		return content, nil, false
+	}
+	if c.blockOnSecrets {
+		c.logf("Not sending %s: it contains %d secrets (%s)\n", path, len(redactions), describeRedactions(redactions))
+		return "", redactions, true
+	}
+	c.logf("Redacted %d secrets from %s before sending it (%s)\n", len(redactions), path, describeRedactions(redactions))
+	return redacted, redactions, false
+}
+
+// warnUnrestored logs placeholders that the model dropped from its output
+func (c *Converter) warnUnrestored(path string, redactions []Redaction) {
+	for _, r := range redactions {
+		if !r.Restored {
+			c.logf("Warning: %s (%s, line %d) is missing from the output for %s; check that the secret is still used\n", r.Placeholder, r.Rule, r.Line, path)
+		}
+	}
+}
+
+// describeRedactions lists where secrets were found, e.g. "AWS access key on line 3"
+func describeRedactions(redactions []Redaction) string {
+	parts := make([]string, len(redactions))
+	for i, r := range redactions {
+		parts[i] = fmt.Sprintf("%s on line %d", r.Rule, r.Line)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,108 @@
+package converter
+
+// pythonStdlib lists the standard library modules that must not be declared as dependencies,
+// as given by sys.stdlib_module_names
+var pythonStdlib = toSet(
+	"__future__", "_abc", "_aix_support", "_ast", "_asyncio", "_bisect", "_blake2", "_bootsubprocess",
+	"_bz2", "_codecs", "_codecs_cn", "_codecs_hk", "_codecs_iso2022", "_codecs_jp", "_codecs_kr",
+	"_codecs_tw", "_collections", "_collections_abc", "_compat_pickle", "_compression", "_contextvars",
+	"_crypt", "_csv", "_ctypes", "_curses", "_curses_panel", "_datetime", "_dbm", "_decimal",
+	"_elementtree", "_frozen_importlib", "_frozen_importlib_external", "_functools", "_gdbm",
+	"_hashlib", "_heapq", "_imp", "_io", "_json", "_locale", "_lsprof", "_lzma", "_markupbase", "_md5",
+	"_msi", "_multibytecodec", "_multiprocessing", "_opcode", "_operator", "_osx_support",
+	"_overlapped", "_pickle", "_posixshmem", "_posixsubprocess", "_py_abc", "_pydecimal", "_pyio",
+	"_queue", "_random", "_scproxy", "_sha1", "_sha256", "_sha3", "_sha512", "_signal", "_sitebuiltins",
+	"_socket", "_sqlite3", "_sre", "_ssl", "_stat", "_statistics", "_string", "_strptime", "_struct",
+	"_symtable", "_thread", "_threading_local", "_tkinter", "_tokenize", "_tracemalloc", "_typing",
+	"_uuid", "_warnings", "_weakref", "_weakrefset", "_winapi", "_zoneinfo", "abc", "aifc",
+	"antigravity", "argparse", "array", "ast", "asynchat", "asyncio", "asyncore", "atexit", "audioop",
+	"base64", "bdb", "binascii", "bisect", "builtins", "bz2", "cProfile", "calendar", "cgi", "cgitb",
+	"chunk", "cmath", "cmd", "code", "codecs", "codeop", "collections", "colorsys", "compileall",
+	"concurrent", "configparser", "contextlib", "contextvars", "copy", "copyreg", "crypt", "csv",
+	"ctypes", "curses", "dataclasses", "datetime", "dbm", "decimal", "difflib", "dis", "distutils",
+	"doctest", "email", "encodings", "ensurepip", "enum", "errno", "faulthandler", "fcntl", "filecmp",
+	"fileinput", "fnmatch", "fractions", "ftplib", "functools", "gc", "genericpath", "getopt",
+	"getpass", "gettext", "glob", "graphlib", "grp", "gzip", "hashlib", "heapq", "hmac", "html", "http",
+	"idlelib", "imaplib", "imghdr", "imp", "importlib", "inspect", "io", "ipaddress", "itertools",
+	"json", "keyword", "lib2to3", "linecache", "locale", "logging", "lzma", "mailbox", "mailcap",
+	"marshal", "math", "mimetypes", "mmap", "modulefinder", "msilib", "msvcrt", "multiprocessing",
+	"netrc", "nis", "nntplib", "nt", "ntpath", "nturl2path", "numbers", "opcode", "operator",
+	"optparse", "os", "ossaudiodev", "pathlib", "pdb", "pickle", "pickletools", "pipes", "pkgutil",
+	"platform", "plistlib", "poplib", "posix", "posixpath", "pprint", "profile", "pstats", "pty", "pwd",
+	"py_compile", "pyclbr", "pydoc", "pydoc_data", "pyexpat", "queue", "quopri", "random", "re",
+	"readline", "reprlib", "resource", "rlcompleter", "runpy", "sched", "secrets", "select",
+	"selectors", "shelve", "shlex", "shutil", "signal", "site", "smtpd", "smtplib", "sndhdr", "socket",
+	"socketserver", "spwd", "sqlite3", "sre_compile", "sre_constants", "sre_parse", "ssl", "stat",
+	"statistics", "string", "stringprep", "struct", "subprocess", "sunau", "symtable", "sys",
+	"sysconfig", "syslog", "tabnanny", "tarfile", "telnetlib", "tempfile", "termios", "textwrap",
+	"this", "threading", "time", "timeit", "tkinter", "token", "tokenize", "tomllib", "trace",
+	"traceback", "tracemalloc", "tty", "turtle", "turtledemo", "types", "typing", "unicodedata",
+	"unittest", "urllib", "uu", "uuid", "venv", "warnings", "wave", "weakref", "webbrowser", "winreg",
+	"winsound", "wsgiref", "xdrlib", "xml", "xmlrpc", "zipapp", "zipfile", "zipimport", "zlib",
+	"zoneinfo",
+)
+
+// nodeBuiltins lists the Node.js core modules that must not be declared as dependencies, as given
+// by require("module").builtinModules with the "node:" prefix removed. Modules such as node:test that
+// only exist under the prefix are not listed, since any "node:" import is a core module.
+var nodeBuiltins = toSet(
+	"_http_agent", "_http_client", "_http_common", "_http_incoming", "_http_outgoing", "_http_server",
+	"_stream_duplex", "_stream_passthrough", "_stream_readable", "_stream_transform", "_stream_wrap",
+	"_stream_writable", "_tls_common", "_tls_wrap", "assert", "assert/strict", "async_hooks", "buffer",
+	"child_process", "cluster", "console", "constants", "crypto", "dgram", "diagnostics_channel", "dns",
+	"dns/promises", "domain", "events", "fs", "fs/promises", "http", "http2", "https", "inspector",
+	"inspector/promises", "module", "net", "os", "path", "path/posix", "path/win32", "perf_hooks",
+	"process", "punycode", "querystring", "readline", "readline/promises", "repl", "stream",
+	"stream/consumers", "stream/promises", "stream/web", "string_decoder", "sys", "timers",
+	"timers/promises", "tls", "trace_events", "tty", "url", "util", "util/types", "v8", "vm", "wasi",
+	"worker_threads", "zlib",
+)
+
+// pythonDistributions maps Python import names to the names their distributions are
+// installed under, where the two differ
+var pythonDistributions = map[string]string{
+	"attr":              "attrs",
+	"Bio":               "biopython",
+	"bs4":               "beautifulsoup4",
+	"Crypto":            "pycryptodome",
+	"cv2":               "opencv-python",
+	"dateutil":          "python-dateutil",
+	"dns":               "dnspython",
+	"docx":              "python-docx",
+	"dotenv":            "python-dotenv",
+	"engineio":          "python-engineio",
+	"fitz":              "PyMuPDF",
+	"gi":                "PyGObject",
+	"git":               "GitPython",
+	"google":            "protobuf",
+	"jinja2":            "Jinja2",
+	"jose":              "python-jose",
+	"jwt":               "PyJWT",
+	"kafka":             "kafka-python",
+	"ldap":              "python-ldap",
+	"Levenshtein":       "python-Levenshtein",
+	"magic":             "python-magic",
+	"markdown":          "Markdown",
+	"multipart":         "python-multipart",
+	"MySQLdb":           "mysqlclient",
+	"nacl":              "PyNaCl",
+	"OpenSSL":           "pyOpenSSL",
+	"PIL":               "Pillow",
+	"pkg_resources":     "setuptools",
+	"pptx":              "python-pptx",
+	"psycopg2":          "psycopg2-binary",
+	"pydantic_settings": "pydantic-settings",
+	"sentry_sdk":        "sentry-sdk",
+	"serial":            "pyserial",
+	"skimage":           "scikit-image",
+	"sklearn":           "scikit-learn",
+	"slugify":           "python-slugify",
+	"socketio":          "python-socketio",
+	"telegram":          "python-telegram-bot",
+	"usb":               "pyusb",
+	"win32api":          "pywin32",
+	"win32con":          "pywin32",
+	"wx":                "wxPython",
+	"yaml":              "PyYAML",
+	"zmq":               "pyzmq",
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TargetSpec is a parsed -lang value. Besides a plain language name it accepts a
+// version after "@", a platform after "/" and space-separated modifiers, as in
+// "python@3.12", "typescript@5 strict" or "kotlin/jvm".
+type TargetSpec struct {
+	Name      string
+	Platform  string
+	Version   string
+	Modifiers []string
+}
+
+// ParseTargetSpec splits a target specification into its parts
+func ParseTargetSpec(spec string) TargetSpec {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return TargetSpec{}
+	}
+
+	var t TargetSpec
+	head := fields[0]
+	if i := strings.Index(head, "@"); i >= 0 {
+		head, t.Version = head[:i], head[i+1:]
+	}
+	// Platforms may appear before or after the version: kotlin/jvm@1.9 or kotlin@1.9/jvm
+	if i := strings.Index(t.Version, "/"); i >= 0 {
+		t.Version, t.Platform = t.Version[:i], t.Version[i+1:]
+	}
+	if i := strings.Index(head, "/"); i >= 0 {
+		head, t.Platform = head[:i], head[i+1:]
+	}
+	t.Name = head
+
+	for _, modifier := range fields[1:] {
+		t.Modifiers = append(t.Modifiers, strings.ToLower(modifier))
+	}
+	return t
+}
+
+// Describe renders the target for prompts and reports, e.g. "TypeScript 5 (strict)"
+func (t TargetSpec) Describe() string {
+	name := t.Name
+	if lang, ok := languages.lookup(t.Name); ok {
+		name = lang.Name
+	}
+	if t.Platform != "" {
+		name += "/" + strings.ToUpper(t.Platform)
+	}
+	if t.Version != "" {
+		name += " " + t.Version
+	}
+	if len(t.Modifiers) > 0 {
+		name += " (" + strings.Join(t.Modifiers, ", ") + ")"
+	}
+	return name
+}
+
+// guidance returns prompt instructions for the requested version, platform and modifiers
+func (t TargetSpec) guidance() []string {
+	var guidance []string
+	name := t.Name
+	if lang, ok := languages.lookup(t.Name); ok {
+		name = lang.Name
+	}
+
+	if t.Version != "" {
+		guidance = append(guidance, fmt.Sprintf("Target %s %s: use only language features and standard library APIs available in that version, and prefer its idioms over older ones.", name, t.Version))
+	}
+	if t.Platform != "" {
+		guidance = append(guidance, fmt.Sprintf("Target the %s/%s platform and only use libraries available there.", name, strings.ToUpper(t.Platform)))
+	}
+	for _, modifier := range t.Modifiers {
+		switch modifier {
+		case "strict":
+			guidance = append(guidance, fmt.Sprintf("The code must compile under %s's strictest checking mode (for TypeScript, \"strict\": true) without suppressions.", name))
+		default:
+			guidance = append(guidance, fmt.Sprintf("Apply the %q setting for %s.", modifier, name))
+		}
+	}
+	return guidance
+}
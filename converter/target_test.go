@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseTargetSpec tests parsing of versioned, platform-specific and modified targets
+func TestParseTargetSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		want     TargetSpec
+		describe string
+	}{
+		{"python", TargetSpec{Name: "python"}, "Python"},
+		{"python@3.12", TargetSpec{Name: "python", Version: "3.12"}, "Python 3.12"},
+		{"typescript@5 strict", TargetSpec{Name: "typescript", Version: "5", Modifiers: []string{"strict"}}, "TypeScript 5 (strict)"},
+		{"c++@20", TargetSpec{Name: "c++", Version: "20"}, "C++ 20"},
+		{"kotlin/jvm", TargetSpec{Name: "kotlin", Platform: "jvm"}, "Kotlin/JVM"},
+		{"kotlin@1.9/jvm", TargetSpec{Name: "kotlin", Platform: "jvm", Version: "1.9"}, "Kotlin/JVM 1.9"},
+	}
+
+	for _, tt := range tests {
+		got := ParseTargetSpec(tt.spec)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTargetSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if d := got.Describe(); d != tt.describe {
+			t.Errorf("ParseTargetSpec(%q).Describe() = %q, want %q", tt.spec, d, tt.describe)
+		}
+	}
+}
+
+// TestVersionedTargetConversion tests that versioned targets keep the plain extension and reach the prompt
+func TestVersionedTargetConversion(t *testing.T) {
+	var prompt string
+	defer setupMockProvider(func(p string) (string, error) {
+		prompt = p
+		return "print('hi')", nil
+	})()
+
+	c := NewConverter("in", "out", "python@3.12")
+	_, ext, err := c.convertCode(defaultProvider(), "print 'hi'\n", "Python", "hi.py")
+	if err != nil {
+		t.Fatalf("convertCode() error = %v", err)
+	}
+	if ext != ".py" {
+		t.Errorf("convertCode() extension = %q, want %q", ext, ".py")
+	}
+	if !strings.Contains(prompt, "Convert the following Python 2 code") || !strings.Contains(prompt, "Target Python 3.12") {
+		t.Errorf("Prompt does not mention the source dialect and target version:\n%s", prompt)
+	}
+}
+
+// TestDetectDialect tests source dialect detection
+func TestDetectDialect(t *testing.T) {
+	tests := []struct {
+		lang    string
+		content string
+		want    string
+	}{
+		{"Python", "print \"hello\"\n", "Python 2"},
+		{"Python", "for i in xrange(3):\n    pass\n", "Python 2"},
+		{"Python", "name = 'x'\nprint(f'hi {name}')\n", "Python 3"},
+		{"Python", "x = 1\n", ""},
+		{"JavaScript", "var x = 1;\nfunction f() { return x; }\n", "ES5"},
+		{"JavaScript", "const fs = require('fs');\n", "ES2015+ CommonJS"},
+		{"JavaScript", "import fs from 'fs';\nexport const x = 1;\n", "ES2015+ ESM"},
+		{"Java", "record Point(int x, int y) {}\n", "Java 16+"},
+		{"Java", "list.forEach(x -> System.out.println(x));\n", "Java 8+"},
+		{"Go", "func Map[T any](xs []T) {}\n", "Go 1.18+"},
+		{"C++", "if constexpr (N > 0) {}\n", "C++17"},
+	}
+
+	for _, tt := range tests {
+		if got := detectDialect(tt.lang, tt.content); got != tt.want {
+			t.Errorf("detectDialect(%s, %q) = %q, want %q", tt.lang, tt.content, got, tt.want)
+		}
+	}
+}
+
+// TestValidatorCommand tests versioned validator selection and modifier arguments
+func TestValidatorCommand(t *testing.T) {
+	lang := &Language{
+		Validator:          []string{"sh", "{file}"},
+		VersionedValidator: []string{"no-such-tool-{version}", "{file}"},
+		ModifierArgs:       map[string][]string{"strict": {"-e"}},
+	}
+
+	got := validatorCommand(lang, ParseTargetSpec("x@9 strict"))
+	want := []string{"sh", "-e", "{file}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("validatorCommand() = %q, want %q (falling back when the versioned tool is missing)", got, want)
+	}
+
+	// Legacy Java versions are passed to javac as the release they stand for
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "javac"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to create fake javac: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	java, _ := languages.lookup("java")
+	got = validatorCommand(java, ParseTargetSpec("java@1.8"))
+	if want := []string{"javac", "--release", "8", "-d", "{tmp}", "{file}"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("validatorCommand(java@1.8) = %q, want %q", got, want)
+	}
+}
+
+// TestRunValidator tests that validator exit codes map to validation results
+func TestRunValidator(t *testing.T) {
+	lang := &Language{Name: "Test", Validator: []string{"grep", "-q", "valid", "{file}"}}
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.txt")
+	bad := filepath.Join(dir, "bad.txt")
+	os.WriteFile(good, []byte("valid code"), 0644)
+	os.WriteFile(bad, []byte("broken code"), 0644)
+
+	if r := runValidator(lang, TargetSpec{}, good); !r.Passed || r.Skipped {
+		t.Errorf("runValidator(good) = %+v, want passed", r)
+	}
+	if r := runValidator(lang, TargetSpec{}, bad); r.Passed || r.Skipped {
+		t.Errorf("runValidator(bad) = %+v, want failed", r)
+	}
+	if r := runValidator(&Language{Name: "None"}, TargetSpec{}, good); !r.Skipped {
+		t.Errorf("runValidator() without a validator = %+v, want skipped", r)
+	}
+}
@@ -0,0 +1,9 @@
+from util.greet import greet
+
+
+def main() -> None:
+    print(greet("world"))
+
+
+if __name__ == "__main__":
+    main()
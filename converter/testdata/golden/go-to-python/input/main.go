@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/greeter/util"
+)
+
+func main() {
+	fmt.Println(util.Greet("world"))
+}
@@ -0,0 +1,17 @@
+package billing;
+
+import java.math.BigDecimal;
+import java.util.List;
+
+public final class InvoiceTotal {
+    public record Line(int quantity, BigDecimal unitPrice) {}
+
+    private InvoiceTotal() {}
+
+    /** Sums quantity times unit price over the invoice lines. */
+    public static BigDecimal invoiceTotal(List<Line> lines) {
+        return lines.stream()
+                .map(line -> line.unitPrice().multiply(BigDecimal.valueOf(line.quantity())))
+                .reduce(BigDecimal.ZERO, BigDecimal::add);
+    }
+}
@@ -0,0 +1,6 @@
+from decimal import Decimal
+
+
+def invoice_total(lines: list[tuple[int, Decimal]]) -> Decimal:
+    """Sum quantity times unit price over the invoice lines."""
+    return sum((qty * price for qty, price in lines), Decimal("0"))
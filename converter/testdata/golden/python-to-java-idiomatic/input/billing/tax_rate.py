@@ -0,0 +1,5 @@
+DEFAULT_RATE = 0.2
+
+
+def tax_rate(country: str) -> float:
+    return {"DE": 0.19, "FR": 0.2}.get(country, DEFAULT_RATE)
@@ -0,0 +1,15 @@
+package billing;
+
+import java.util.Map;
+
+public final class TaxRate {
+    public static final double DEFAULT_RATE = 0.2;
+
+    private static final Map<String, Double> RATES = Map.of("DE", 0.19, "FR", 0.2);
+
+    private TaxRate() {}
+
+    public static double taxRate(String country) {
+        return RATES.getOrDefault(country, DEFAULT_RATE);
+    }
+}
@@ -0,0 +1,8 @@
+package util
+
+import "strings"
+
+// Greet returns a greeting for name
+func Greet(name string) string {
+	return "Hello, " + strings.TrimSpace(name) + "!"
+}
@@ -0,0 +1,139 @@
+package converter
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// testPrefixes and testSuffixes are stripped from test file names to find the name of the code under test
+var (
+	testPrefixes = []string{"test_", "test-"}
+	testSuffixes = []string{".test", ".spec", ".Tests", "_test", "_spec", "Tests", "Test", "Spec", "Suite"}
+)
+
+// testDirNames are directories that only exist to hold tests and are dropped when tests are relocated
+var testDirNames = map[string]bool{"test": true, "tests": true, "__tests__": true, "spec": true, "specs": true, "t": true}
+
+// isTestFile reports whether a source file holds tests according to its language's test patterns
+func isTestFile(relPath, sourceLang string) bool {
+	lang, ok := languages.lookup(sourceLang)
+	return ok && lang.IsTestFile(relPath)
+}
+
+// testSubject returns the name of the code a test file exercises, e.g. "parser" for
+// parser_test.go, test_parser.py or parser.spec.ts
+func testSubject(fileName string) string {
+	stem := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	for _, prefix := range testPrefixes {
+		if len(stem) > len(prefix) && strings.HasPrefix(stem, prefix) {
+			return stem[len(prefix):]
+		}
+	}
+	for _, suffix := range testSuffixes {
+		if len(stem) > len(suffix) && strings.HasSuffix(stem, suffix) {
+			return stem[:len(stem)-len(suffix)]
+		}
+	}
+	return stem
+}
+
+// testSubjectDirs returns the directories of the code under test, dropping test-only
+// directories such as tests/ and the src/test/java prefix of JVM projects
+func testSubjectDirs(relDir string) []string {
+	dirs := splitDir(relDir)
+	if len(dirs) >= 3 && dirs[0] == "src" && dirs[1] == "test" {
+		dirs = dirs[3:]
+	}
+
+	out := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		if !testDirNames[d] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// testOutputPath places a converted test file in the target language's
+// conventional test location, falling back to the regular output path
+func (c *Converter) testOutputPath(relPath string) string {
+	target, ok := languages.lookup(c.targetLang)
+	if !ok || target.TestPath == "" {
+		return c.outputPathFor(relPath, true)
+	}
+
+	dirs := testSubjectDirs(filepath.Dir(relPath))
+	if rule, ok := layoutRules[target.Extension()]; ok && c.layout == LayoutIdiomatic {
+		dirs = idiomaticDirs(rule, dirs)
+	}
+
+	rel := expandTestPath(target, testSubject(filepath.Base(relPath)), dirs)
+	if c.layout == LayoutFlat {
+		rel = path.Base(rel)
+	}
+	return filepath.Join(c.outputDir, filepath.FromSlash(rel))
+}
+
+// expandTestPath fills in a language's test path template for the named code in dirs,
+// returning a slash-separated path relative to the project root
+func expandTestPath(lang *Language, name string, dirs []string) string {
+	rel := strings.NewReplacer(
+		"{dir}", strings.Join(dirs, "/"),
+		"{name}", name,
+		"{Name}", pascalCase(name),
+		"{snake_name}", snakeCase(name),
+	).Replace(lang.TestPath)
+	return strings.TrimPrefix(path.Clean("/"+rel), "/")
+}
+
+// linkTestSubjects records, for every test job, where the code it exercises is
+// written, preferring a subject in the same directory
+func linkTestSubjects(jobs []fileJob) {
+	for i := range jobs {
+		if !jobs[i].test {
+			continue
+		}
+		subject := testSubject(filepath.Base(jobs[i].relPath))
+		dir := strings.Join(testSubjectDirs(filepath.Dir(jobs[i].relPath)), "/")
+
+		for _, job := range jobs {
+			if job.test || !job.convert {
+				continue
+			}
+			stem := strings.TrimSuffix(filepath.Base(job.relPath), filepath.Ext(job.relPath))
+			if stem != subject {
+				continue
+			}
+			sameDir := strings.Join(splitDir(filepath.Dir(job.relPath)), "/") == dir
+			if jobs[i].subjectOutput == "" || sameDir {
+				jobs[i].subjectOutput = job.outputPath
+			}
+			if sameDir {
+				break
+			}
+		}
+	}
+}
+
+// testGuidance returns prompt instructions for converting a test file into the target's test framework
+func (c *Converter) testGuidance(job fileJob) []string {
+	target, ok := languages.lookup(c.targetLang)
+	if !ok {
+		return nil
+	}
+
+	framework := target.TestFramework
+	if framework == "" {
+		framework = "the standard test framework for " + target.Name
+	}
+	guidance := []string{fmt.Sprintf("This is a test file. Convert it into idiomatic %s tests using %s, keeping every test case and assertion.", target.Name, framework)}
+
+	if job.subjectOutput != "" {
+		if rel, err := filepath.Rel(filepath.Dir(job.outputPath), job.subjectOutput); err == nil {
+			guidance = append(guidance, fmt.Sprintf("The code under test is converted to %s, relative to this test file; import it from there.", filepath.ToSlash(rel)))
+		}
+	}
+	return guidance
+}
@@ -0,0 +1,100 @@
+package converter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTestSubject tests that test affixes are stripped to find the code under test
+func TestTestSubject(t *testing.T) {
+	tests := map[string]string{
+		"parser_test.go":       "parser",
+		"test_parser.py":       "parser",
+		"parser.spec.ts":       "parser",
+		"ParserTest.java":      "Parser",
+		"parser_spec.rb":       "parser",
+		"Parser.Tests.ps1":     "Parser",
+		"test.py":              "test",
+		"ParserTests.cs":       "Parser",
+		"user_service.test.js": "user_service",
+	}
+	for name, want := range tests {
+		if got := testSubject(name); got != want {
+			t.Errorf("testSubject(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestTestOutputPath tests that converted tests go to the target's conventional test location
+func TestTestOutputPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		layout Layout
+		input  string
+		want   string
+	}{
+		{"go to python", "python", LayoutMirror, "pkg/parser/parser_test.go", "tests/pkg/parser/test_parser.py"},
+		{"python to go", "go", LayoutMirror, "tests/test_user_service.py", "user_service_test.go"},
+		{"java to typescript", "typescript", LayoutMirror, "src/test/java/com/acme/ParserTest.java", "com/acme/Parser.test.ts"},
+		{"typescript to java", "java", LayoutMirror, "src/__tests__/parser.spec.ts", "src/test/java/src/ParserTest.java"},
+		{"go to rust", "rust", LayoutMirror, "pkg/parser_test.go", "tests/parser.rs"},
+		{"flat layout", "python", LayoutFlat, "pkg/parser_test.go", "test_parser.py"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConverter("in", "out", tt.target)
+			c.SetLayout(tt.layout)
+			got := c.testOutputPath(filepath.FromSlash(tt.input))
+			if want := filepath.Join("out", filepath.FromSlash(tt.want)); got != want {
+				t.Errorf("testOutputPath(%q) = %q, want %q", tt.input, got, want)
+			}
+		})
+	}
+}
+
+// TestConvertTestFiles tests that test files are converted with framework guidance and listed separately
+func TestConvertTestFiles(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{"parser/parser.go": "// parser/parser.go", "parser/parser_test.go": "// parser/parser_test.go"})
+
+	var prompts []string
+	defer setupMockProvider(func(prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return "pass", nil
+	})()
+
+	var log bytes.Buffer
+	c := NewConverter(root, out, "python")
+	c.SetScaffold(false)
+	c.SetLogOutput(&log)
+	if err := c.Convert(); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	var testPrompt string
+	for _, prompt := range prompts {
+		if strings.Contains(prompt, "parser_test.go") {
+			testPrompt = prompt
+		}
+	}
+	if !strings.Contains(testPrompt, "pytest") {
+		t.Errorf("Expected the test prompt to target pytest, got %q", testPrompt)
+	}
+	if !strings.Contains(testPrompt, "../../parser/parser.py") {
+		t.Errorf("Expected the test prompt to point at the code under test, got %q", testPrompt)
+	}
+
+	for _, f := range c.Report().Files {
+		if f.Source == "parser/parser_test.go" && (!f.Test || f.Output != "tests/parser/test_parser.py") {
+			t.Errorf("Unexpected report entry for the test file: %+v", f)
+		}
+	}
+	if !strings.Contains(log.String(), "Summary: 1 converted, 0 copied\nTests: 1 converted, 0 copied\n") {
+		t.Errorf("Expected tests to be summarised separately, got %q", log.String())
+	}
+}
@@ -0,0 +1,174 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// replayProviderName is the name of the provider that serves recorded responses
+const replayProviderName = "replay"
+
+// TranscriptEntry is one request to a provider, as recorded in a JSONL transcript
+type TranscriptEntry struct {
+	Time       time.Time      `json:"time"`
+	Provider   string         `json:"provider"`
+	Model      string         `json:"model,omitempty"`
+	Params     map[string]any `json:"params,omitempty"`
+	PromptHash string         `json:"prompt_hash"`
+	Prompt     string         `json:"prompt"`
+	Response   string         `json:"response"`
+	Error      string         `json:"error,omitempty"`
+	Usage      Usage          `json:"usage"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+// Transcript appends every prompt and response to a JSONL file
+type Transcript struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// OpenTranscript opens a transcript file for appending, creating it if needed
+func OpenTranscript(path string) (*Transcript, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript %s: %w", path, err)
+	}
+	return &Transcript{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record appends an entry to the transcript
+func (t *Transcript) Record(entry TranscriptEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.enc.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write transcript entry: %w", err)
+	}
+	return nil
+}
+
+// Close closes the transcript file
+func (t *Transcript) Close() error {
+	return t.file.Close()
+}
+
+// SetTranscript records every request the converter sends to a provider
+func (c *Converter) SetTranscript(t *Transcript) {
+	c.transcript = t
+}
+
+// promptHash identifies a prompt in a transcript
+func promptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// transcriptProvider records the requests sent to the provider it wraps
+type transcriptProvider struct {
+	Provider
+	transcript *Transcript
+	log        io.Writer
+}
+
+func (p transcriptProvider) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p transcriptProvider) WithParams(params ModelParams) Provider {
+	return transcriptProvider{Provider: withParams(p.Provider, params), transcript: p.transcript, log: p.log}
+}
+
+func (p transcriptProvider) Complete(prompt string) (Completion, error) {
+	start := time.Now()
+	completion, err := complete(p.Provider, prompt)
+	entry := TranscriptEntry{
+		Time:       start.UTC(),
+		Provider:   p.Provider.Name(),
+		Model:      completion.Model,
+		Params:     completion.Params,
+		PromptHash: promptHash(prompt),
+		Prompt:     prompt,
+		Response:   completion.Text,
+		Usage:      completion.Usage,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	// A transcript that cannot be written must not abort the conversion itself
+	if recordErr := p.transcript.Record(entry); recordErr != nil {
+		fmt.Fprintf(p.log, "Warning: %v\n", recordErr)
+	}
+	return completion, err
+}
+
+// replayProvider serves the responses recorded in a transcript, matching requests by
+// prompt hash. Repeated prompts get their recorded responses in order, and the last
+// one once those run out.
+type replayProvider struct {
+	path      string
+	mu        sync.Mutex
+	responses map[string][]TranscriptEntry
+	served    map[string]int
+}
+
+// LoadReplayProvider reads a transcript and returns a provider that answers from it
+// without contacting any model, so a recorded run can be reproduced offline
+func LoadReplayProvider(path string) (Provider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript %s: %w", path, err)
+	}
+	defer f.Close()
+
+	p := &replayProvider{path: path, responses: map[string][]TranscriptEntry{}, served: map[string]int{}}
+	dec := json.NewDecoder(f)
+	for line := 1; ; line++ {
+		var entry TranscriptEntry
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse transcript %s, entry %d: %w", path, line, err)
+		}
+		hash := entry.PromptHash
+		if hash == "" {
+			hash = promptHash(entry.Prompt)
+		}
+		p.responses[hash] = append(p.responses[hash], entry)
+	}
+	return p, nil
+}
+
+func (p *replayProvider) Name() string { return replayProviderName }
+
+func (p *replayProvider) Generate(prompt string) (string, error) {
+	completion, err := p.Complete(prompt)
+	return completion.Text, err
+}
+
+func (p *replayProvider) Complete(prompt string) (Completion, error) {
+	hash := promptHash(prompt)
+	p.mu.Lock()
+	entries := p.responses[hash]
+	i := p.served[hash]
+	p.served[hash]++
+	p.mu.Unlock()
+
+	if len(entries) == 0 {
+		return Completion{}, fmt.Errorf("no response recorded in %s for prompt %s", p.path, hash[:12])
+	}
+	entry := entries[min(i, len(entries)-1)]
+	if entry.Error != "" {
+		return Completion{}, errors.New(entry.Error)
+	}
+	return Completion{Text: entry.Response, Model: entry.Model, Params: entry.Params, Usage: entry.Usage}, nil
+}
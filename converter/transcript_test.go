@@ -0,0 +1,66 @@
+package converter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTranscriptRecordAndReplay tests that recorded requests are served back by prompt hash
+func TestTranscriptRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	transcript, err := OpenTranscript(path)
+	if err != nil {
+		t.Fatalf("OpenTranscript() error = %v", err)
+	}
+
+	calls := 0
+	defer setupMockProvider(func(prompt string) (string, error) {
+		calls++
+		return "answer " + string(rune('0'+calls)), nil
+	})()
+
+	recorded := transcriptProvider{Provider: defaultProvider(), transcript: transcript, log: io.Discard}
+	for _, prompt := range []string{"first", "second", "first"} {
+		if _, err := recorded.Generate(prompt); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+	transcript.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read transcript: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Fatalf("Expected 3 transcript entries, got %d:\n%s", lines, data)
+	}
+	if !strings.Contains(string(data), `"provider":"openai"`) || !strings.Contains(string(data), `"prompt_hash":"`+promptHash("second")+`"`) {
+		t.Errorf("Unexpected transcript:\n%s", data)
+	}
+
+	replay, err := LoadReplayProvider(path)
+	if err != nil {
+		t.Fatalf("LoadReplayProvider() error = %v", err)
+	}
+	// Repeated prompts get their responses in order, then the last one again
+	for _, tt := range []struct{ prompt, want string }{
+		{"first", "answer 1"},
+		{"second", "answer 2"},
+		{"first", "answer 3"},
+		{"first", "answer 3"},
+	} {
+		got, err := replay.Generate(tt.prompt)
+		if err != nil || got != tt.want {
+			t.Errorf("Generate(%q) = %q, %v, want %q", tt.prompt, got, err, tt.want)
+		}
+	}
+	if _, err := replay.Generate("never recorded"); err == nil {
+		t.Errorf("Expected an error for a prompt missing from the transcript")
+	}
+	if calls != 3 {
+		t.Errorf("Expected replay not to call the model, got %d calls", calls)
+	}
+}
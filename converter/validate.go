@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// validationTimeout bounds how long a single validator command may run
+const validationTimeout = 60 * time.Second
+
+// ValidationResult records the outcome of running a language's validator on an output file
+type ValidationResult struct {
+	Tool    string `json:"tool,omitempty"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+// SetValidate enables running the target language's validator on every converted file
+func (c *Converter) SetValidate(enabled bool) {
+	c.validate = enabled
+}
+
+// validatorVersions turn a requested target version into the form its versioned
+// validator expects, keyed by target extension, so that java@1.8 runs javac --release 8
+var validatorVersions = map[string]func(string) string{
+	".java": javaRelease,
+}
+
+// validatorCommand picks the validator for a target, preferring the versioned
+// command when a version was requested and its tool is installed
+func validatorCommand(lang *Language, target TargetSpec) []string {
+	var command []string
+	if target.Version != "" && len(lang.VersionedValidator) > 0 {
+		version := target.Version
+		if normalize, ok := validatorVersions[lang.Extension()]; ok {
+			version = normalize(version)
+		}
+		versioned := make([]string, len(lang.VersionedValidator))
+		for i, arg := range lang.VersionedValidator {
+			versioned[i] = strings.ReplaceAll(arg, "{version}", version)
+		}
+		if _, err := exec.LookPath(versioned[0]); err == nil {
+			command = versioned
+		}
+	}
+	if command == nil {
+		command = append([]string(nil), lang.Validator...)
+	}
+	if len(command) == 0 {
+		return nil
+	}
+
+	// Modifier arguments such as --strict go straight after the executable
+	for _, modifier := range target.Modifiers {
+		if args := lang.ModifierArgs[modifier]; len(args) > 0 {
+			command = append(command[:1], append(append([]string(nil), args...), command[1:]...)...)
+		}
+	}
+	return command
+}
+
+// validateFile runs the target language's validator on a converted file
+func (c *Converter) validateFile(path string) ValidationResult {
+	lang, ok := languages.lookup(c.targetLang)
+	if !ok {
+		return ValidationResult{Skipped: true, Output: "unknown target language"}
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return runValidator(lang, c.target, path)
+}
+
+// validateContent validates code that has not been written to disk yet by saving
+// it under its base name in a scratch directory
+func (c *Converter) validateContent(name, content string) ValidationResult {
+	dir, err := os.MkdirTemp("", "code-converter-check")
+	if err != nil {
+		return ValidationResult{Skipped: true, Output: err.Error()}
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(name))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return ValidationResult{Skipped: true, Output: err.Error()}
+	}
+	return c.validateFile(path)
+}
+
+// runValidator executes a validator command, skipping it when the tool is not installed
+func runValidator(lang *Language, target TargetSpec, path string) ValidationResult {
+	command := validatorCommand(lang, target)
+	if len(command) == 0 {
+		return ValidationResult{Skipped: true, Output: "no validator configured for " + lang.Name}
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return ValidationResult{Tool: command[0], Skipped: true, Output: command[0] + " is not installed"}
+	}
+
+	tmp, err := os.MkdirTemp("", "code-converter-validate")
+	if err != nil {
+		return ValidationResult{Tool: command[0], Skipped: true, Output: err.Error()}
+	}
+	defer os.RemoveAll(tmp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+	defer cancel()
+
+	args := expandCommand(command, path, tmp)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = tmp
+	out, err := cmd.CombinedOutput()
+
+	result := ValidationResult{Tool: command[0], Passed: err == nil}
+	if err != nil {
+		result.Output = strings.TrimSpace(string(out))
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Output = "validator timed out"
+		} else if result.Output == "" {
+			result.Output = err.Error()
+		}
+	}
+	return result
+}
@@ -0,0 +1,612 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultVerifyTimeout bounds a single program or test suite run
+const defaultVerifyTimeout = 2 * time.Minute
+
+// Normalizer masks output that legitimately differs between runs. When the
+// pattern has capture groups only the groups are replaced, so "ID: (\d+)"
+// keeps the label and masks the number.
+type Normalizer struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+
+	// minLength keeps matches shorter than it, for lengths RE2 cannot combine with the pattern
+	minLength int
+}
+
+// defaultNormalizers mask timestamps, durations and generated identifiers. They
+// use non-capturing groups so the whole match is replaced.
+var defaultNormalizers = []Normalizer{
+	{Name: "timestamp", Pattern: regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|\s?[+-]\d{2}:?\d{2}(?:\s[A-Z]{3,4})?)?`), Replacement: "<TIMESTAMP>"},
+	{Name: "date", Pattern: regexp.MustCompile(`\b\d{4}[-/]\d{2}[-/]\d{2}\b`), Replacement: "<DATE>"},
+	{Name: "time", Pattern: regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(?:\.\d+)?\b`), Replacement: "<TIME>"},
+	{Name: "uuid", Pattern: regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), Replacement: "<UUID>"},
+	{Name: "address", Pattern: regexp.MustCompile(`(?i)\b0x[0-9a-f]{6,}\b`), Replacement: "<ADDRESS>"},
+	// Long hex identifiers contain at least one letter, so large decimal numbers are kept
+	{Name: "hex-id", Pattern: regexp.MustCompile(`(?i)\b[0-9a-f]*[a-f][0-9a-f]*\b`), Replacement: "<HEX>", minLength: 16},
+	{Name: "duration", Pattern: regexp.MustCompile(`\b\d+(?:\.\d+)?(?:ns|µs|us|ms)\b`), Replacement: "<DURATION>"},
+}
+
+// NewNormalizer compiles a user-supplied normalisation pattern
+func NewNormalizer(pattern string) (Normalizer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Normalizer{}, fmt.Errorf("invalid normalisation pattern %q: %w", pattern, err)
+	}
+	return Normalizer{Name: pattern, Pattern: re, Replacement: "<NORMALIZED>"}, nil
+}
+
+// apply masks every match of the normalizer in text
+func (n Normalizer) apply(text string) string {
+	if n.Pattern.NumSubexp() == 0 {
+		return n.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if len(match) < n.minLength {
+				return match
+			}
+			return n.Replacement
+		})
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range n.Pattern.FindAllStringSubmatchIndex(text, -1) {
+		for g := 1; g <= n.Pattern.NumSubexp(); g++ {
+			start, end := m[2*g], m[2*g+1]
+			if start < last || start < 0 {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(n.Replacement)
+			last = end
+		}
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// VerifyCase is one set of inputs given to both programs
+type VerifyCase struct {
+	Name  string   `json:"name"`
+	Args  []string `json:"args,omitempty"`
+	Stdin string   `json:"stdin,omitempty"`
+}
+
+// RunResult captures a single program or test suite run
+type RunResult struct {
+	Command  []string `json:"command,omitempty"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr"`
+	ExitCode int      `json:"exit_code"`
+	Duration string   `json:"duration,omitempty"`
+}
+
+// VerifyResult compares the original and converted runs of one entry point and case
+type VerifyResult struct {
+	Entry      string     `json:"entry"`
+	Converted  string     `json:"converted"`
+	Case       string     `json:"case,omitempty"`
+	Passed     bool       `json:"passed"`
+	Skipped    bool       `json:"skipped,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Mismatches []string   `json:"mismatches,omitempty"`
+	Diff       string     `json:"diff,omitempty"`
+	Original   *RunResult `json:"original,omitempty"`
+	Result     *RunResult `json:"result,omitempty"`
+}
+
+// VerifyReport summarises a verify run
+type VerifyReport struct {
+	Passed  int            `json:"passed"`
+	Failed  int            `json:"failed"`
+	Skipped int            `json:"skipped"`
+	Results []VerifyResult `json:"results"`
+}
+
+// Verifier runs original and converted programs side by side and compares their behaviour
+type Verifier struct {
+	original     string
+	converted    string
+	cases        []VerifyCase
+	normalizers  []Normalizer
+	testSuites   bool
+	ignoreStderr bool
+	timeout      time.Duration
+	log          io.Writer
+}
+
+// NewVerifier creates a Verifier for an original program or project and its conversion;
+// both paths must be files or both directories
+func NewVerifier(original, converted string) *Verifier {
+	return &Verifier{
+		original:    original,
+		converted:   converted,
+		cases:       []VerifyCase{{Name: "default"}},
+		normalizers: append([]Normalizer(nil), defaultNormalizers...),
+		timeout:     defaultVerifyTimeout,
+		log:         os.Stdout,
+	}
+}
+
+// SetCases replaces the default case, which runs both programs without arguments or input
+func (v *Verifier) SetCases(cases []VerifyCase) {
+	if len(cases) > 0 {
+		v.cases = cases
+	}
+}
+
+// AddNormalizer adds a normalisation rule on top of the built-in ones
+func (v *Verifier) AddNormalizer(n Normalizer) {
+	v.normalizers = append(v.normalizers, n)
+}
+
+// SetTestSuites also runs each side's test suite and compares whether they pass
+func (v *Verifier) SetTestSuites(enabled bool) {
+	v.testSuites = enabled
+}
+
+// SetIgnoreStderr compares only stdout and exit codes
+func (v *Verifier) SetIgnoreStderr(enabled bool) {
+	v.ignoreStderr = enabled
+}
+
+// SetTimeout bounds each program or test suite run
+func (v *Verifier) SetTimeout(timeout time.Duration) {
+	v.timeout = timeout
+}
+
+// SetLogOutput redirects progress messages, which go to stdout by default
+func (v *Verifier) SetLogOutput(w io.Writer) {
+	v.log = w
+}
+
+// LoadVerifyCases reads a JSON array of cases from path
+func LoadVerifyCases(path string) ([]VerifyCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cases file %s: %w", path, err)
+	}
+	var cases []VerifyCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse cases file %s: %w", path, err)
+	}
+	for i := range cases {
+		if cases[i].Name == "" {
+			cases[i].Name = fmt.Sprintf("case %d", i+1)
+		}
+	}
+	return cases, nil
+}
+
+// Verify runs every entry point pair with every case and, if enabled, both test suites
+func (v *Verifier) Verify() (VerifyReport, error) {
+	pairs, err := v.entryPairs()
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	var report VerifyReport
+	for _, pair := range pairs {
+		for _, vc := range v.cases {
+			report.add(v.verifyEntry(pair, vc))
+		}
+	}
+	if v.testSuites {
+		report.add(v.verifySuites())
+	}
+	return report, nil
+}
+
+// add records a result and updates the totals
+func (r *VerifyReport) add(result VerifyResult) {
+	switch {
+	case result.Skipped:
+		r.Skipped++
+	case result.Passed:
+		r.Passed++
+	default:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// entryPair is an original entry point and its converted counterpart
+type entryPair struct {
+	original, converted string
+}
+
+// entryPairs matches original entry points with converted files. A conversion
+// report in the converted directory is used when present; otherwise files are
+// matched by directory and name.
+func (v *Verifier) entryPairs() ([]entryPair, error) {
+	origInfo, err := os.Stat(v.original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access original %s: %w", v.original, err)
+	}
+	convInfo, err := os.Stat(v.converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access converted %s: %w", v.converted, err)
+	}
+	if origInfo.IsDir() != convInfo.IsDir() {
+		return nil, fmt.Errorf("original and converted must both be files or both be directories")
+	}
+	if !origInfo.IsDir() {
+		return []entryPair{{v.original, v.converted}}, nil
+	}
+
+	entries, err := findEntryPoints(v.original)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 && !v.testSuites {
+		return nil, fmt.Errorf("no entry points found in %s", v.original)
+	}
+
+	outputs := reportOutputs(filepath.Join(v.converted, "conversion-report.json"))
+	candidates, err := listFiles(v.converted)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []entryPair
+	for _, rel := range entries {
+		converted := outputs[filepath.ToSlash(rel)]
+		if converted == "" {
+			converted = matchConverted(rel, candidates)
+		}
+		if converted == "" {
+			pairs = append(pairs, entryPair{original: filepath.Join(v.original, rel)})
+			continue
+		}
+		pairs = append(pairs, entryPair{filepath.Join(v.original, rel), filepath.Join(v.converted, filepath.FromSlash(converted))})
+	}
+	return pairs, nil
+}
+
+// findEntryPoints returns the relative paths of files under root that define a program entry point
+func findEntryPoints(root string) ([]string, error) {
+	files, err := listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, rel := range files {
+		lang, ok := detectLanguage(rel)
+		re, hasPattern := entryPointPatterns[lang]
+		if !ok || !hasPattern || isTestFile(rel, lang) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", rel, err)
+		}
+		if re.Match(content) {
+			entries = append(entries, rel)
+		}
+	}
+	return entries, nil
+}
+
+// listFiles returns every file under root, relative to it, skipping ignored directories
+func listFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && shouldIgnoreDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// reportOutputs maps source paths to output paths from a conversion report, if one exists
+func reportOutputs(path string) map[string]string {
+	outputs := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return outputs
+	}
+	var report Report
+	if json.Unmarshal(data, &report) != nil {
+		return outputs
+	}
+	for _, f := range report.Files {
+		if f.Action == actionConverted {
+			outputs[f.Source] = f.Output
+		}
+	}
+	return outputs
+}
+
+// matchConverted finds the converted counterpart of an entry point: a source file
+// with the same name in the same directory, or failing that the only one anywhere
+func matchConverted(rel string, candidates []string) string {
+	dir := filepath.Dir(rel)
+	stem := strings.ToLower(snakeCase(strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))))
+
+	var sameName []string
+	for _, candidate := range candidates {
+		if _, ok := detectLanguage(candidate); !ok {
+			continue
+		}
+		candidateStem := strings.ToLower(snakeCase(strings.TrimSuffix(filepath.Base(candidate), filepath.Ext(candidate))))
+		if candidateStem != stem {
+			continue
+		}
+		if filepath.Dir(candidate) == dir {
+			return filepath.ToSlash(candidate)
+		}
+		sameName = append(sameName, candidate)
+	}
+	if len(sameName) == 1 {
+		return filepath.ToSlash(sameName[0])
+	}
+	return ""
+}
+
+// verifyEntry runs one entry point pair with one case and compares the results
+func (v *Verifier) verifyEntry(pair entryPair, vc VerifyCase) VerifyResult {
+	result := VerifyResult{Entry: pair.original, Converted: pair.converted, Case: vc.Name}
+	if pair.converted == "" {
+		result.Skipped = true
+		result.Reason = "no converted counterpart found"
+		return result
+	}
+
+	original, err := v.runProgram(pair.original, vc)
+	if err != nil {
+		result.Skipped, result.Reason = true, err.Error()
+		return result
+	}
+	converted, err := v.runProgram(pair.converted, vc)
+	if err != nil {
+		result.Skipped, result.Reason = true, err.Error()
+		return result
+	}
+
+	result.Original, result.Result = &original, &converted
+	v.compare(&result, original, converted, true)
+	return result
+}
+
+// verifySuites runs the test suites of both trees and compares whether they pass
+func (v *Verifier) verifySuites() VerifyResult {
+	result := VerifyResult{Entry: v.original, Converted: v.converted, Case: "test suite"}
+
+	original, err := v.runSuite(v.original)
+	if err != nil {
+		result.Skipped, result.Reason = true, err.Error()
+		return result
+	}
+	converted, err := v.runSuite(v.converted)
+	if err != nil {
+		result.Skipped, result.Reason = true, err.Error()
+		return result
+	}
+
+	// Test frameworks print different reports, so only the outcome is compared
+	result.Original, result.Result = &original, &converted
+	v.compare(&result, original, converted, false)
+	return result
+}
+
+// compare records every difference between two runs after normalisation
+func (v *Verifier) compare(result *VerifyResult, original, converted RunResult, compareOutput bool) {
+	if original.ExitCode != converted.ExitCode {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("exit code %d != %d", original.ExitCode, converted.ExitCode))
+	}
+
+	if compareOutput {
+		streams := []struct{ name, original, converted string }{{"stdout", original.Stdout, converted.Stdout}}
+		if !v.ignoreStderr {
+			streams = append(streams, struct{ name, original, converted string }{"stderr", original.Stderr, converted.Stderr})
+		}
+		for _, s := range streams {
+			want, got := v.normalize(s.original), v.normalize(s.converted)
+			if want != got {
+				result.Mismatches = append(result.Mismatches, s.name+" differs")
+				result.Diff += unifiedDiff("original/"+s.name, "converted/"+s.name, want, got)
+			}
+		}
+	}
+	result.Passed = len(result.Mismatches) == 0
+}
+
+// normalize applies every normalizer and ignores trailing whitespace
+func (v *Verifier) normalize(output string) string {
+	for _, n := range v.normalizers {
+		output = n.apply(output)
+	}
+	return trimOutput(output)
+}
+
+// trimOutput strips trailing whitespace from every line and ends non-empty output with one newline
+func trimOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	output = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	if output != "" {
+		output += "\n"
+	}
+	return output
+}
+
+// runProgram runs a single source file with its language's run command from the file's directory
+func (v *Verifier) runProgram(path string, vc VerifyCase) (RunResult, error) {
+	name, ok := detectLanguage(path)
+	if !ok {
+		return RunResult{}, fmt.Errorf("cannot tell the language of %s", path)
+	}
+	lang, _ := languages.lookup(name)
+	if len(lang.Run) == 0 {
+		return RunResult{}, fmt.Errorf("no run command configured for %s", lang.Name)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return RunResult{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return v.run(lang.Run, abs, filepath.Dir(abs), vc)
+}
+
+// runSuite runs the test command of the dominant language under root
+func (v *Verifier) runSuite(root string) (RunResult, error) {
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+	lang, err := dominantLanguage(root)
+	if err != nil {
+		return RunResult{}, err
+	}
+	if len(lang.TestCommand) == 0 {
+		return RunResult{}, fmt.Errorf("no test command configured for %s", lang.Name)
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return RunResult{}, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	return v.run(lang.TestCommand, abs, abs, VerifyCase{})
+}
+
+// dominantLanguage returns the language with the most source files under root
+func dominantLanguage(root string) (*Language, error) {
+	files, err := listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	best := ""
+	for _, rel := range files {
+		if name, ok := detectLanguage(rel); ok {
+			counts[name]++
+			if best == "" || counts[name] > counts[best] {
+				best = name
+			}
+		}
+	}
+	if best == "" {
+		return nil, fmt.Errorf("no source files found in %s", root)
+	}
+	lang, _ := languages.lookup(best)
+	return lang, nil
+}
+
+// run executes a command with the case's arguments and stdin, capturing its output
+func (v *Verifier) run(command []string, file, dir string, vc VerifyCase) (RunResult, error) {
+	return executeCommand(command, file, dir, vc, v.timeout, v.log)
+}
+
+// executeCommand runs a registry command from dir, expanding {file} and {tmp}, and
+// captures its output. An error means the command could not be run at all; a
+// command that times out is reported with exit code -1.
+func executeCommand(command []string, file, dir string, vc VerifyCase, timeout time.Duration, log io.Writer) (RunResult, error) {
+	env, command := splitCommandEnv(command)
+	if len(command) == 0 {
+		return RunResult{}, fmt.Errorf("command %q has no executable", strings.Join(env, " "))
+	}
+	// Commands with a slash, such as vendor/bin/phpunit, are resolved relative to dir when run
+	if _, err := exec.LookPath(command[0]); err != nil && !strings.Contains(command[0], "/") {
+		return RunResult{}, fmt.Errorf("%s is not installed", command[0])
+	}
+
+	tmp, err := os.MkdirTemp("", "code-converter-run")
+	if err != nil {
+		return RunResult{}, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := append(expandCommand(command, file, tmp), vc.Args...)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdin = strings.NewReader(vc.Stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	fmt.Fprintf(log, "Running %s\n", strings.Join(append(env, args...), " "))
+	start := time.Now()
+	err = cmd.Run()
+
+	result := RunResult{
+		Command:  append(env, args...),
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		// A hang is a behaviour difference like any other, so it is reported rather than skipped
+		result.ExitCode = -1
+		result.Stderr += fmt.Sprintf("\ntimed out after %s\n", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return result, fmt.Errorf("failed to run %s: %w", strings.Join(args, " "), err)
+	}
+	return result, nil
+}
+
+// envAssignment matches a leading NAME=value argument of a registry command
+var envAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// splitCommandEnv separates the NAME=value arguments a command starts with from the command itself
+func splitCommandEnv(command []string) (env, args []string) {
+	i := 0
+	for i < len(command) && envAssignment.MatchString(command[i]) {
+		i++
+	}
+	return command[:i:i], command[i:]
+}
+
+// WriteVerifyReport saves a verify report as JSON
+func WriteVerifyReport(report VerifyReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
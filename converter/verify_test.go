@@ -0,0 +1,132 @@
+package converter
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNormalizers tests the built-in rules and capture-group masking of custom rules
+func TestNormalizers(t *testing.T) {
+	custom, err := NewNormalizer(`ID: (\d+)`)
+	if err != nil {
+		t.Fatalf("NewNormalizer() error = %v", err)
+	}
+	v := NewVerifier("", "")
+	v.AddNormalizer(custom)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Created: 2024-05-01T10:11:12+02:00\n", "Created: <TIMESTAMP>\n"},
+		{"Created: 2024-05-01T10:11:12\n", "Created: <TIMESTAMP>\n"},
+		{"request 3f2504e0-4f89-11d3-9a0c-0305e82c3301 done", "request <UUID> done\n"},
+		{"took 12.5ms", "took <DURATION>\n"},
+		{"ID: 4821\nName: x  \n\n", "ID: <NORMALIZED>\nName: x\n"},
+		{"session 9f86d081884c7d65 ok", "session <HEX> ok\n"},
+		{"count 1234567890123456789 beef", "count 1234567890123456789 beef\n"},
+	}
+	for _, tt := range tests {
+		if got := v.normalize(tt.input); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestMatchConverted tests pairing entry points with converted files
+func TestMatchConverted(t *testing.T) {
+	candidates := []string{
+		filepath.Join("cmd", "tool", "main.py"),
+		filepath.Join("cmd", "other", "main.py"),
+		filepath.Join("server", "http_server.py"),
+		"README.md",
+	}
+	tests := []struct {
+		entry string
+		want  string
+	}{
+		{filepath.Join("cmd", "tool", "main.go"), "cmd/tool/main.py"},
+		{filepath.Join("server", "HttpServer.java"), "server/http_server.py"},
+		{filepath.Join("main.go"), ""},
+	}
+	for _, tt := range tests {
+		if got := matchConverted(tt.entry, candidates); got != tt.want {
+			t.Errorf("matchConverted(%q) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+// TestVerify tests comparing the behaviour of two programs run with the same input
+func TestVerify(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"original.sh": "read name\necho \"hello $name\"\necho \"at $(date -u +%Y-%m-%dT%H:%M:%SZ)\"\n",
+		"same.sh":     "read name\necho \"hello $name\"\necho \"at 2001-02-03T04:05:06Z\"\n",
+		"broken.sh":   "read name\necho \"hi $name\"\nexit 3\n",
+	})
+
+	cases := []VerifyCase{{Name: "world", Stdin: "world\n"}}
+
+	v := NewVerifier(filepath.Join(root, "original.sh"), filepath.Join(root, "same.sh"))
+	v.SetLogOutput(io.Discard)
+	v.SetCases(cases)
+	report, err := v.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Passed != 1 || report.Failed != 0 {
+		t.Errorf("Expected matching programs to pass, got %+v", report.Results)
+	}
+
+	v = NewVerifier(filepath.Join(root, "original.sh"), filepath.Join(root, "broken.sh"))
+	v.SetLogOutput(io.Discard)
+	v.SetCases(cases)
+	report, err = v.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Failed != 1 {
+		t.Fatalf("Expected differing programs to fail, got %+v", report.Results)
+	}
+	mismatches := strings.Join(report.Results[0].Mismatches, "; ")
+	if !strings.Contains(mismatches, "exit code 0 != 3") || !strings.Contains(mismatches, "stdout differs") {
+		t.Errorf("Unexpected mismatches: %s", mismatches)
+	}
+	if !strings.Contains(report.Results[0].Diff, "+hi world") {
+		t.Errorf("Expected a diff of the outputs, got %q", report.Results[0].Diff)
+	}
+}
+
+// TestVerifyGoPackage tests that a converted Go program is run as a package, so its entry point can call code in its other files
+func TestVerifyGoPackage(t *testing.T) {
+	for _, tool := range []string{"bash", "go"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+	}
+
+	root := t.TempDir()
+	writeSourceFiles(t, root, map[string]string{
+		"original.sh":  "read name\necho \"hello $name\"\n",
+		"out/main.go":  "package main\n\nimport (\n\t\"bufio\"\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tname, _ := bufio.NewReader(os.Stdin).ReadString('\\n')\n\tfmt.Println(greet(name))\n}\n",
+		"out/greet.go": "package main\n\nimport \"strings\"\n\nfunc greet(name string) string {\n\treturn \"hello \" + strings.TrimSpace(name)\n}\n",
+	})
+
+	v := NewVerifier(filepath.Join(root, "original.sh"), filepath.Join(root, "out", "main.go"))
+	v.SetLogOutput(io.Discard)
+	v.SetCases([]VerifyCase{{Name: "world", Stdin: "world\n"}})
+	report, err := v.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Passed != 1 {
+		t.Errorf("Expected the Go package to match the original, got %+v", report.Results)
+	}
+}
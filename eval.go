@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// runEval converts a suite of programs with one or more configurations and reports how well each did
+func runEval(args []string) int {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	suite := flags.String("suite", "", "Directory with one subdirectory per program: case.json, src/ and optional tests/<language>/ (required)")
+	var targets, providerNames inputList
+	flags.Var(&targets, "lang", "Target language to convert every program to, with optional version and modifiers; comma-separated or repeated (required)")
+	flags.Var(&providerNames, "provider", "Provider to evaluate, one configuration each; comma-separated or repeated (default openai)")
+	configPath := flags.String("config", "", "JSON file with configurations, providers and model prices: {\"configurations\": [{\"name\", \"provider\", \"guidance\"}], \"providers\": {...}, \"pricing\": {\"model\": {\"input\", \"output\"}}}")
+	fake := flags.String("fake", "", "Register a provider named fake that answers from a JSON fixture file")
+	replay := flags.String("replay", "", "Register a provider named replay that answers from a recorded JSONL transcript")
+	timeout := flags.Duration("timeout", 2*time.Minute, "Time limit for each program or test suite run")
+	workDir := flags.String("work", "", "Directory to keep the converted programs in (a temporary directory by default)")
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	egress := addEgressFlags(flags)
+	reportPath := flags.String("report", "", "JSON file to write the evaluation report to")
+	flags.Parse(args)
+
+	if *suite == "" || len(targets) == 0 {
+		fmt.Println("Error: suite and lang flags are required")
+		flags.Usage()
+		return 1
+	}
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if err := registerOfflineProviders(*fake, *replay); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	// The policy is loaded first, since configurations may name the providers it declares
+	policy, err := egress.loadPolicy()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	evaluator := converter.NewEvaluator(*suite, targets)
+	evaluator.SetTimeout(*timeout)
+	evaluator.SetWorkDir(*workDir)
+	evaluator.SetEgressPolicy(policy)
+	evaluator.SetBlockOnSecrets(*egress.blockOnSecrets)
+
+	var configs []converter.EvalConfig
+	if *configPath != "" {
+		loaded, prices, err := converter.LoadEvalConfigs(*configPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		configs = loaded
+		evaluator.SetPrices(prices)
+	}
+	for _, name := range providerNames {
+		configs = append(configs, converter.EvalConfig{Name: name, Provider: name})
+	}
+	evaluator.SetConfigs(configs)
+
+	report, err := evaluator.Run()
+	if err != nil {
+		fmt.Printf("Error during evaluation: %v\n", err)
+		return 1
+	}
+
+	fmt.Println()
+	if err := report.WriteTable(os.Stdout); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	if *reportPath != "" {
+		if err := converter.WriteEvalReport(report, *reportPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
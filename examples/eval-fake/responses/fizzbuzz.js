@@ -0,0 +1,14 @@
+function fizzbuzz(n) {
+  if (n % 15 === 0) return "FizzBuzz";
+  if (n % 3 === 0) return "Fizz";
+  if (n % 5 === 0) return "Buzz";
+  return String(n);
+}
+
+if (process.argv.length !== 3) {
+  console.error("usage: fizzbuzz.js N");
+  process.exit(2);
+}
+for (let i = 1; i <= Number(process.argv[2]); i++) {
+  console.log(fizzbuzz(i));
+}
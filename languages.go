@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// runLanguages lists the registered languages and whether their tools are installed
+func runLanguages(args []string) int {
+	flags := flag.NewFlagSet("languages", flag.ExitOnError)
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	flags.Parse(args)
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tALIASES\tEXTENSIONS\tCOMMENT\tFORMATTER\tVALIDATOR")
+	for _, lang := range converter.Languages() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			lang.Name,
+			orDash(strings.Join(lang.Aliases, ", ")),
+			orDash(strings.Join(lang.Extensions, " ")),
+			orDash(lang.LineComment),
+			toolStatus(lang.Formatter),
+			toolStatus(lang.Validator))
+	}
+	w.Flush()
+	return 0
+}
+
+// toolStatus names a command's executable and whether it is on the PATH
+func toolStatus(command []string) string {
+	if len(command) == 0 {
+		return "-"
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return command[0] + " (not installed)"
+	}
+	return command[0]
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -11,71 +11,206 @@ import (
 )
 
 func main() {
+	args := os.Args[1:]
+
+	// "convert" is the default command, so the subcommand name is optional
+	if len(args) > 0 && args[0] == "convert" {
+		args = args[1:]
+	} else if len(args) > 0 && args[0] == "languages" {
+		os.Exit(runLanguages(args[1:]))
+	} else if len(args) > 0 && args[0] == "modernize" {
+		os.Exit(runModernize(args[1:]))
+	} else if len(args) > 0 && args[0] == "verify" {
+		os.Exit(runVerify(args[1:]))
+	} else if len(args) > 0 && args[0] == "check-api" {
+		os.Exit(runCheckAPI(args[1:]))
+	} else if len(args) > 0 && args[0] == "eval" {
+		os.Exit(runEval(args[1:]))
+	} else if len(args) > 0 && args[0] == "compare" {
+		os.Exit(runCompare(args[1:]))
+	}
+
+	os.Exit(runConvert(args))
+}
+
+// runConvert parses the convert flags and runs a directory, file list or stdin conversion
+func runConvert(args []string) int {
 	// Define command-line flags
-	inputDir := flag.String("input", "", "Input project directory (required)")
-	outputDir := flag.String("output", "", "Output directory for converted code (required)")
-	targetLang := flag.String("lang", "", "Target programming language (required)")
-	
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	var inputs inputList
+	flags.Var(&inputs, "input", "Input directory, file, comma-separated list, glob (src/**/*.go), @filelist or - to read paths from stdin; may be repeated (required)")
+	outputDir := flags.String("output", "", "Output directory for converted code (required unless streaming)")
+	targetLang := flags.String("lang", "", "Target language, optionally with a version, platform and modifiers: python@3.12, kotlin/jvm, \"typescript@5 strict\" (required)")
+	sourceLang := flags.String("from", "", "Source language of every text file, overriding detection; binary files are copied (detected if omitted)")
+	layoutName := flags.String("layout", "mirror", "Output layout: mirror, idiomatic or flat")
+	collisionName := flags.String("on-collision", "error", "How to handle inputs that map to the same output path: error or rename")
+	var include, exclude inputList
+	flags.Var(&include, "include", "Only process files whose path below the input root matches one of these globs (src/**/*.go); comma-separated or repeated")
+	flags.Var(&exclude, "exclude", "Skip files whose path below the input root matches one of these globs; comma-separated or repeated")
+	concurrency := flags.Int("concurrency", 1, "Number of files converted at once")
+	validate := flags.Bool("validate", false, "Run the target language's validator on every converted file")
+	providerOptions := addProviderFlags(flags)
+	samples := flags.Int("samples", 1, "Generate this many candidates per file with varied temperature and seed and keep the best scoring one")
+	reviewer := flags.String("review", "", "Provider that reviews every converted file against its source and lists semantic discrepancies in the report")
+	reviewFix := flags.Bool("review-fix", false, "Ask the reviewer for a corrected version of files with discrepancies")
+	var fallback inputList
+	flags.Var(&fallback, "fallback", "Providers to try in order when the selected one fails on a file; comma-separated or repeated")
+	fallbackOn := flags.String("fallback-on", "", "Comma-separated failures that move a file to the next fallback provider: error, rate_limit, context, refusal, invalid (default all)")
+	checkAPI := flags.Bool("check-api", false, "Compare the public API of every converted file with its source and report missing, renamed or extra symbols")
+	characterize := flags.Bool("characterize", false, "Generate characterisation tests for untested sources, keep those that pass against the original code and convert them too")
+	scaffold := flags.Bool("scaffold", true, "Generate a project manifest and README for the target language")
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	reportName := flags.String("report", "conversion-report.json", "JSON report file, relative to the output directory; empty to disable")
+
 	// Parse flags
-	flag.Parse()
-	
+	flags.Parse(args)
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if *targetLang != "" {
+		if _, err := converter.ResolveLanguage(converter.ParseTargetSpec(*targetLang).Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -lang: %v\n", err)
+			return 1
+		}
+	}
+
+	if *sourceLang != "" {
+		lang, err := converter.ResolveLanguage(*sourceLang)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -from: %v\n", err)
+			return 1
+		}
+		*sourceLang = lang.Name
+	}
+
+	// Without an output directory, "-input -", or no input with source code piped
+	// in, streams source code from stdin to stdout
+	streamInput := (len(inputs) == 1 && inputs[0] == "-") || (len(inputs) == 0 && stdinIsPiped())
+	if *outputDir == "" && streamInput {
+		return runStream(providerOptions, *targetLang, *sourceLang)
+	}
+
 	// Validate required flags
-	if *inputDir == "" || *outputDir == "" || *targetLang == "" {
+	if len(inputs) == 0 || *outputDir == "" || *targetLang == "" {
 		fmt.Println("Error: input, output, and lang flags are required")
-		flag.Usage()
-		os.Exit(1)
+		flags.Usage()
+		return 1
+	}
+
+	if *reviewFix && *reviewer == "" {
+		fmt.Println("Error: -review-fix needs a reviewer; name one with -review")
+		return 1
+	}
+
+	fallbackConditions, err := converter.ParseFallbackConditions(*fallbackOn)
+	if err != nil {
+		fmt.Printf("Error: -fallback-on: %v\n", err)
+		return 1
 	}
-	
-	// Convert relative paths to absolute
-	absInputDir, err := filepath.Abs(*inputDir)
+
+	layout, err := converter.ParseLayout(*layoutName)
 	if err != nil {
-		fmt.Printf("Error resolving input directory path: %v\n", err)
-		os.Exit(1)
+		fmt.Printf("Error: %v\n", err)
+		return 1
 	}
-	
+
+	collisions, err := converter.ParseCollisionPolicy(*collisionName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	inputPaths, err := expandInputs(inputs, os.Stdin)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
 	absOutputDir, err := filepath.Abs(*outputDir)
 	if err != nil {
 		fmt.Printf("Error resolving output directory path: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
-	
-	// Validate that input directory exists
-	if _, err := os.Stat(absInputDir); os.IsNotExist(err) {
-		fmt.Printf("Error: input directory %s does not exist\n", absInputDir)
-		os.Exit(1)
-	}
-	
+
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(absOutputDir, 0755); err != nil {
 		fmt.Printf("Error creating output directory: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
-	
-	fmt.Printf("Converting code from %s to %s language\n", absInputDir, *targetLang)
+
+	fmt.Printf("Converting code from %s to %s language\n", strings.Join(inputPaths, ", "), *targetLang)
 	fmt.Printf("Output will be saved to %s\n", absOutputDir)
-	
-	inputPaths := strings.Split(*inputDir, ",")
-	for _, path := range inputPaths {
-		path = strings.TrimSpace(path)
-		fileInfo, err := os.Stat(path)
-		if err != nil {
-			fmt.Printf("Error accessing path %s: %v\n", path, err)
-			continue
-		}
-		
-		if fileInfo.IsDir() {
-			// Process directory (existing code)
-			conv := converter.NewConverter(path, *outputDir, *targetLang)
-			if err := conv.Convert(); err != nil {
-				fmt.Printf("Error during directory conversion: %v\n", err)
-			}
-		} else {
-			// Process individual file
-			if err := converter.ConvertFile(path, *outputDir, *targetLang); err != nil {
-				fmt.Printf("Error converting file %s: %v\n", path, err)
-			}
+
+	// All inputs share one converter so relative paths are mirrored from their common root
+	conv := converter.NewMultiConverter(inputPaths, *outputDir, *targetLang)
+	conv.SetScaffold(*scaffold)
+	conv.SetLayout(layout)
+	conv.SetCollisionPolicy(collisions)
+	conv.SetSourceLanguage(*sourceLang)
+	conv.SetValidate(*validate)
+	conv.SetCharacterize(*characterize)
+	conv.SetCheckAPI(*checkAPI)
+	conv.SetSamples(*samples)
+	conv.SetReviewer(*reviewer)
+	conv.SetReviewFix(*reviewFix)
+	conv.SetFallback(fallback)
+	conv.SetFallbackConditions(fallbackConditions)
+	conv.SetConcurrency(*concurrency)
+	if err := conv.SetFilters(include, exclude); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	closeTranscript, err := providerOptions.apply(conv)
+	defer closeTranscript()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	if *reportName != "" {
+		reportPath := *reportName
+		if !filepath.IsAbs(reportPath) {
+			reportPath = filepath.Join(absOutputDir, reportPath)
 		}
+		conv.SetReportPath(reportPath)
 	}
-	
+	if err := conv.Convert(); err != nil {
+		fmt.Printf("Error during conversion: %v\n", err)
+		return 1
+	}
+
 	fmt.Println("Conversion completed successfully!")
-} 
\ No newline at end of file
+	return 0
+}
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// runStream converts source code from stdin to stdout, keeping every log message on stderr
+func runStream(providerOptions *providerFlags, targetLang, sourceLang string) int {
+	if targetLang == "" {
+		fmt.Fprintln(os.Stderr, "Error: lang flag is required")
+		return 1
+	}
+
+	conv := converter.NewConverter("-", "", targetLang)
+	conv.SetLogOutput(os.Stderr)
+	closeTranscript, err := providerOptions.apply(conv)
+	defer closeTranscript()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := conv.ConvertStream(os.Stdin, os.Stdout, sourceLang); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
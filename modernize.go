@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// runModernize upgrades sources within their language family, writing a unified
+// diff to stdout or a file, or rewriting the files in place with -write
+func runModernize(args []string) int {
+	flags := flag.NewFlagSet("modernize", flag.ExitOnError)
+	var inputs inputList
+	flags.Var(&inputs, "input", "Input directory, file, comma-separated list, glob, @filelist or - to read paths from stdin; may be repeated (required)")
+	targetLang := flags.String("lang", "", "Target language and version in the same family as the sources: python@3.12, java@21, \"typescript@5 strict\" (required)")
+	sourceLang := flags.String("from", "", "Source language of every text file, overriding detection; binary files are copied (detected if omitted)")
+	write := flags.Bool("write", false, "Rewrite the source files in place instead of writing a patch")
+	patchPath := flags.String("patch", "-", "File to write the unified diff to, or - for stdout; ignored with -write")
+	validate := flags.Bool("validate", false, "Run the target language's validator on every modernized file")
+	providerOptions := addProviderFlags(flags)
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	reportPath := flags.String("report", "", "JSON report file; empty to disable")
+	flags.Parse(args)
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if len(inputs) == 0 || *targetLang == "" {
+		fmt.Fprintln(os.Stderr, "Error: input and lang flags are required")
+		flags.Usage()
+		return 1
+	}
+	if _, err := converter.ResolveLanguage(converter.ParseTargetSpec(*targetLang).Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -lang: %v\n", err)
+		return 1
+	}
+	if *sourceLang != "" {
+		lang, err := converter.ResolveLanguage(*sourceLang)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -from: %v\n", err)
+			return 1
+		}
+		*sourceLang = lang.Name
+	}
+
+	inputPaths, err := expandInputs(inputs, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// The patch may go to stdout, so progress messages always go to stderr
+	var patch io.Writer = os.Stdout
+	if !*write && *patchPath != "-" && *patchPath != "" {
+		f, err := os.Create(*patchPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating patch file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		patch = f
+	}
+
+	conv := converter.NewMultiConverter(inputPaths, "", *targetLang)
+	conv.SetLogOutput(os.Stderr)
+	conv.SetSourceLanguage(*sourceLang)
+	conv.SetWriteInPlace(*write)
+	conv.SetValidate(*validate)
+	closeTranscript, err := providerOptions.apply(conv)
+	defer closeTranscript()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	conv.SetReportPath(*reportPath)
+	if err := conv.Modernize(patch); err != nil {
+		fmt.Fprintf(os.Stderr, "Error during modernization: %v\n", err)
+		return 1
+	}
+	return 0
+}
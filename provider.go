@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// egressFlags restrict what every command that sends code to a model may send
+type egressFlags struct {
+	policy         *string
+	blockOnSecrets *bool
+}
+
+// addEgressFlags defines the egress policy options on flags
+func addEgressFlags(flags *flag.FlagSet) egressFlags {
+	return egressFlags{
+		policy:         flags.String("policy", "", "JSON egress policy mapping paths, globs, languages or license markers to the providers they may be sent to"),
+		blockOnSecrets: flags.Bool("block-on-secrets", false, "Refuse to send files that contain secrets to the model instead of redacting them; blocked files are listed in the report"),
+	}
+}
+
+// loadPolicy reads the egress policy, registering the providers it declares, or returns nil without one
+func (f egressFlags) loadPolicy() (*converter.EgressPolicy, error) {
+	if *f.policy == "" {
+		return nil, nil
+	}
+	return converter.LoadEgressPolicy(*f.policy)
+}
+
+// providerFlags are the options shared by every command that sends code to a model
+type providerFlags struct {
+	egressFlags
+	provider      *string
+	record        *string
+	replay        *string
+	fake          *string
+	model         *string
+	deterministic *bool
+	params        converter.ModelParams
+}
+
+// addProviderFlags defines the model provider options on flags
+func addProviderFlags(flags *flag.FlagSet) *providerFlags {
+	f := &providerFlags{
+		egressFlags:   addEgressFlags(flags),
+		provider:      flags.String("provider", "openai", "Model provider to send code to: openai or one declared in the policy file"),
+		record:        flags.String("record", "", "Append every prompt, response, model and token usage to this JSONL transcript"),
+		replay:        flags.String("replay", "", "Answer prompts from a recorded JSONL transcript instead of contacting a model"),
+		fake:          flags.String("fake", "", "Answer prompts from a JSON fixture file of canned responses instead of contacting a model"),
+		model:         flags.String("model", "", "Model the openai provider sends prompts to (default "+converter.OpenAIModel+")"),
+		deterministic: flags.Bool("deterministic", false, "Use temperature 0, a fixed seed and a stable prompt order so reruns on unchanged input give the same output where the provider allows it"),
+	}
+	flags.Func("temperature", "Sampling temperature, from 0 to 2 (default: the model's)", func(s string) error {
+		v, err := parseFloatFlag(s, 0, 2)
+		if err != nil {
+			return err
+		}
+		f.params.Temperature = &v
+		return nil
+	})
+	flags.Func("top-p", "Nucleus sampling probability mass, from 0 to 1 (default: the model's)", func(s string) error {
+		v, err := parseFloatFlag(s, 0, 1)
+		if err != nil {
+			return err
+		}
+		f.params.TopP = &v
+		return nil
+	})
+	flags.Func("max-tokens", "Maximum number of tokens in each response (default: the model's)", func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return fmt.Errorf("must be a positive integer")
+		}
+		f.params.MaxTokens = &v
+		return nil
+	})
+	flags.Func("seed", "Seed for providers that support reproducible sampling", func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		f.params.Seed = &v
+		return nil
+	})
+	flags.Func("system", "System prompt sent ahead of every prompt", func(s string) error {
+		f.params.System = s
+		return nil
+	})
+	return f
+}
+
+// parseFloatFlag parses a flag value that must lie between lo and hi
+func parseFloatFlag(s string, lo, hi float32) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil || float32(v) < lo || float32(v) > hi {
+		return 0, fmt.Errorf("must be a number from %g to %g", lo, hi)
+	}
+	return float32(v), nil
+}
+
+// apply configures conv with the provider options. The returned function closes the
+// transcript and must be called once the converter is done.
+func (f *providerFlags) apply(conv *converter.Converter) (func(), error) {
+	closeTranscript := func() {}
+
+	policy, err := f.loadPolicy()
+	if err != nil {
+		return closeTranscript, err
+	}
+	if policy != nil {
+		conv.SetEgressPolicy(policy)
+	}
+
+	conv.SetProvider(*f.provider)
+	if *f.replay != "" {
+		replay, err := converter.LoadReplayProvider(*f.replay)
+		if err != nil {
+			return closeTranscript, err
+		}
+		converter.RegisterProvider(replay)
+		conv.SetProvider(replay.Name())
+	}
+
+	if *f.fake != "" {
+		fake, err := converter.LoadFakeProvider("fake", *f.fake)
+		if err != nil {
+			return closeTranscript, err
+		}
+		converter.RegisterProvider(fake)
+		conv.SetProvider(fake.Name())
+	}
+
+	if *f.record != "" {
+		transcript, err := converter.OpenTranscript(*f.record)
+		if err != nil {
+			return closeTranscript, err
+		}
+		conv.SetTranscript(transcript)
+		closeTranscript = func() { transcript.Close() }
+	}
+
+	if *f.model != "" {
+		converter.OpenAIModel = *f.model
+	}
+	conv.SetModelParams(f.params)
+	conv.SetDeterministic(*f.deterministic)
+	conv.SetBlockOnSecrets(*f.blockOnSecrets)
+	return closeTranscript, nil
+}
+
+// registerOfflineProviders makes the fake and replay providers available by name,
+// for commands that pick providers per configuration rather than with -provider
+func registerOfflineProviders(fake, replay string) error {
+	if fake != "" {
+		p, err := converter.LoadFakeProvider("fake", fake)
+		if err != nil {
+			return err
+		}
+		converter.RegisterProvider(p)
+	}
+	if replay != "" {
+		p, err := converter.LoadReplayProvider(replay)
+		if err != nil {
+			return err
+		}
+		converter.RegisterProvider(p)
+	}
+	return nil
+}
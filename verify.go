@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/b-eq/code-converter-cli/converter"
+)
+
+// patternList collects repeated flag values verbatim, since patterns may contain commas
+type patternList []string
+
+func (l *patternList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *patternList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runVerify runs the original and converted programs with the same inputs and reports behaviour differences
+func runVerify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	original := flags.String("original", "", "Original program file or project directory (required)")
+	converted := flags.String("converted", "", "Converted program file or project directory (required)")
+	casesPath := flags.String("cases", "", "JSON file with cases to run: [{\"name\": ..., \"args\": [...], \"stdin\": ...}]")
+	programArgs := flags.String("args", "", "Space-separated arguments passed to both programs when no cases file is given")
+	stdinPath := flags.String("stdin", "", "File fed to both programs on stdin when no cases file is given")
+	var normalize patternList
+	flags.Var(&normalize, "normalize", "Regular expression whose matches (or capture groups) are masked before comparing, e.g. 'ID: (\\d+)'; may be repeated")
+	tests := flags.Bool("tests", false, "Also run both test suites and compare whether they pass")
+	ignoreStderr := flags.Bool("ignore-stderr", false, "Compare only stdout and exit codes")
+	timeout := flags.Duration("timeout", 2*time.Minute, "Time limit for each program or test suite run")
+	languageConfig := flags.String("languages", "", "JSON file with additional or extended language definitions")
+	reportPath := flags.String("report", "", "JSON file to write the verification report to")
+	flags.Parse(args)
+
+	if *original == "" || *converted == "" {
+		fmt.Println("Error: original and converted flags are required")
+		flags.Usage()
+		return 1
+	}
+
+	if *languageConfig != "" {
+		if err := converter.LoadLanguageConfig(*languageConfig); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+
+	verifier := converter.NewVerifier(*original, *converted)
+	verifier.SetTestSuites(*tests)
+	verifier.SetIgnoreStderr(*ignoreStderr)
+	verifier.SetTimeout(*timeout)
+	for _, pattern := range normalize {
+		n, err := converter.NewNormalizer(pattern)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		verifier.AddNormalizer(n)
+	}
+
+	if *casesPath != "" {
+		cases, err := converter.LoadVerifyCases(*casesPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		verifier.SetCases(cases)
+	} else if *programArgs != "" || *stdinPath != "" {
+		vc := converter.VerifyCase{Name: "default", Args: strings.Fields(*programArgs)}
+		if *stdinPath != "" {
+			data, err := os.ReadFile(*stdinPath)
+			if err != nil {
+				fmt.Printf("Error reading stdin file: %v\n", err)
+				return 1
+			}
+			vc.Stdin = string(data)
+		}
+		verifier.SetCases([]converter.VerifyCase{vc})
+	}
+
+	report, err := verifier.Verify()
+	if err != nil {
+		fmt.Printf("Error during verification: %v\n", err)
+		return 1
+	}
+
+	for _, r := range report.Results {
+		status := "PASS"
+		if r.Skipped {
+			status = "SKIP"
+		} else if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("%s  %s -> %s [%s]\n", status, r.Entry, r.Converted, r.Case)
+		if r.Skipped {
+			fmt.Printf("      %s\n", r.Reason)
+		}
+		for _, mismatch := range r.Mismatches {
+			fmt.Printf("      %s\n", mismatch)
+		}
+		if r.Diff != "" {
+			fmt.Println(r.Diff)
+		}
+	}
+	fmt.Printf("Verified: %d passed, %d failed, %d skipped\n", report.Passed, report.Failed, report.Skipped)
+
+	if *reportPath != "" {
+		if err := converter.WriteVerifyReport(report, *reportPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+	}
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}